@@ -0,0 +1,108 @@
+package graphix
+
+import "math"
+
+// AABB represents an axis-aligned bounding box [Min[0],Max[0]]×[Min[1],Max[1]]×[Min[2],Max[2]].
+type AABB struct {
+	Min *Vec3
+	Max *Vec3
+}
+
+var _ Volume = (*AABB)(nil)
+
+// NewAABB creates the smallest AABB containing all the given points.
+// With no points, an empty box is returned which contains nothing until extended.
+func NewAABB(ps ...*Vec3) *AABB {
+	inf := math.Inf(1)
+	box := &AABB{Min: NewVec3(inf, inf, inf), Max: NewVec3(-inf, -inf, -inf)}
+	for _, p := range ps {
+		box.Extend(p)
+	}
+	return box
+}
+
+// Extend grows the box to contain p then returns the box.
+func (box *AABB) Extend(p *Vec3) *AABB {
+	for i := range 3 {
+		box.Min[i] = math.Min(box.Min[i], p[i])
+		box.Max[i] = math.Max(box.Max[i], p[i])
+	}
+	return box
+}
+
+// Center stores the center of the box into v then returns v.
+func (box *AABB) Center(v *Vec3) *Vec3 {
+	return v.Scale(v.Add(box.Min, box.Max), .5)
+}
+
+// Size stores the extent of the box along each axis into v then returns v.
+func (box *AABB) Size(v *Vec3) *Vec3 { return v.Sub(box.Max, box.Min) }
+
+func (box *AABB) Contains(p *Vec3) bool {
+	for i := range 3 {
+		if p[i] < box.Min[i] || p[i] > box.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (box *AABB) SignedDistance(p *Vec3) float64 {
+	// See https://iquilezles.org/articles/distfunctions/
+	var q Vec3
+	inside := math.Inf(-1)
+	for i := range 3 {
+		half := (box.Max[i] - box.Min[i]) / 2
+		d := math.Abs(p[i]-(box.Min[i]+half)) - half
+		q[i] = math.Max(d, 0)
+		inside = math.Max(inside, d)
+	}
+	return q.Norm() + math.Min(inside, 0)
+}
+
+func (box *AABB) ClosestPoint(v, p *Vec3) *Vec3 {
+	if !box.Contains(p) {
+		for i := range 3 {
+			v[i] = min(max(p[i], box.Min[i]), box.Max[i])
+		}
+		return v
+	}
+	// Inside: push p onto the nearest face.
+	v.Copy(p)
+	axis, val, best := 0, 0.0, math.Inf(1)
+	for i := range 3 {
+		if d := p[i] - box.Min[i]; d < best {
+			axis, val, best = i, box.Min[i], d
+		}
+		if d := box.Max[i] - p[i]; d < best {
+			axis, val, best = i, box.Max[i], d
+		}
+	}
+	v[axis] = val
+	return v
+}
+
+func (box *AABB) IntersectRay(r *Ray) (float64, bool) {
+	// Slab method, see https://en.wikipedia.org/wiki/Slab_method
+	tmin, tmax := math.Inf(-1), math.Inf(1)
+	for i := range 3 {
+		if r.Dir[i] == 0 {
+			if r.Origin[i] < box.Min[i] || r.Origin[i] > box.Max[i] {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (box.Min[i] - r.Origin[i]) / r.Dir[i]
+		t2 := (box.Max[i] - r.Origin[i]) / r.Dir[i]
+		tmin = math.Max(tmin, math.Min(t1, t2))
+		tmax = math.Min(tmax, math.Max(t1, t2))
+	}
+	if tmin > tmax || tmax < 0 {
+		return 0, false
+	}
+	if tmin >= 0 {
+		return tmin, true
+	}
+	// Origin inside the box, the ray exits at tmax.
+	return tmax, true
+}
@@ -0,0 +1,59 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAABB(t *testing.T) {
+	box := NewAABB(NewVec3(1, 2, 3), NewVec3(-1, 0, 5))
+	assertVec3Equal(t, -1, 0, 3, box.Min, 0)
+	assertVec3Equal(t, 1, 2, 5, box.Max, 0)
+	assertVec3Equal(t, 0, 1, 4, box.Center(BlankVec3()), 1e-8)
+	assertVec3Equal(t, 2, 2, 2, box.Size(BlankVec3()), 1e-8)
+
+	assert.Same(t, box, box.Extend(NewVec3(0, 3, 0)))
+	assertVec3Equal(t, -1, 0, 0, box.Min, 0)
+	assertVec3Equal(t, 1, 3, 5, box.Max, 0)
+
+	assert.True(t, box.Contains(NewVec3(0, 0, 0)))
+	assert.True(t, box.Contains(NewVec3(1, 3, 5)))
+	assert.False(t, box.Contains(NewVec3(0, -.1, 0)))
+
+	// Empty box contains nothing.
+	assert.False(t, NewAABB().Contains(NewVec3(0, 0, 0)))
+}
+
+func TestAABBSignedDistance(t *testing.T) {
+	box := NewAABB(NewVec3(-1, -1, -1), NewVec3(1, 1, 1))
+	assert.InDelta(t, 2, box.SignedDistance(NewVec3(3, 0, 0)), 1e-8)
+	assert.InDelta(t, math.Sqrt(3), box.SignedDistance(NewVec3(2, 2, 2)), 1e-8)
+	assert.InDelta(t, -1, box.SignedDistance(NewVec3(0, 0, 0)), 1e-8)
+	assert.InDelta(t, -.5, box.SignedDistance(NewVec3(0, .5, 0)), 1e-8)
+
+	v := BlankVec3()
+	assertVec3Equal(t, 1, 1, 0, box.ClosestPoint(v, NewVec3(2, 3, 0)), 1e-8)
+	assertVec3Equal(t, 0, 1, 0, box.ClosestPoint(v, NewVec3(0, .5, 0)), 1e-8)
+}
+
+func TestAABBIntersectRay(t *testing.T) {
+	box := NewAABB(NewVec3(-1, -1, -1), NewVec3(1, 1, 1))
+	tt, ok := box.IntersectRay(NewRay(NewVec3(-3, 0, 0), NewVec3(1, 0, 0)))
+	assert.True(t, ok)
+	assert.InDelta(t, 2, tt, 1e-8)
+
+	// Origin inside.
+	tt, ok = box.IntersectRay(NewRay(NewVec3(0, 0, 0), NewVec3(0, 0, 2)))
+	assert.True(t, ok)
+	assert.InDelta(t, .5, tt, 1e-8)
+
+	// Parallel to a slab and outside of it.
+	_, ok = box.IntersectRay(NewRay(NewVec3(-3, 2, 0), NewVec3(1, 0, 0)))
+	assert.False(t, ok)
+
+	// Behind.
+	_, ok = box.IntersectRay(NewRay(NewVec3(3, 0, 0), NewVec3(1, 0, 0)))
+	assert.False(t, ok)
+}
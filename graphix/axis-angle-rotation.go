@@ -7,11 +7,11 @@ type axisAngleRotation struct {
 	mat [3]Vec3
 }
 
-var _ Transform = (*axisAngleRotation)(nil)
+var _ AffineTransform = (*axisAngleRotation)(nil)
 
 // NewAxisAngleRotation creates a transform that rotates a point around axis n by theta.
 // Caller is responsible for passing in a normalized n.
-func NewAxisAngleRotation(n *Vec3, theta float64) Transform {
+func NewAxisAngleRotation(n *Vec3, theta float64) AffineTransform {
 	ct, st := math.Cos(theta), math.Sin(theta)
 	aar := &axisAngleRotation{}
 	// See https://en.wikipedia.org/wiki/Rotation_matrix
@@ -36,3 +36,13 @@ func (aar *axisAngleRotation) Apply(v, u *Vec3) *Vec3 {
 	v[0], v[1], v[2] = aar.mat[0].Dot(u), aar.mat[1].Dot(u), aar.mat[2].Dot(u)
 	return v
 }
+
+func (aar *axisAngleRotation) Mat4() *Mat4 {
+	m := &aar.mat
+	return &Mat4{
+		{m[0][0], m[0][1], m[0][2], 0},
+		{m[1][0], m[1][1], m[1][2], 0},
+		{m[2][0], m[2][1], m[2][2], 0},
+		{0, 0, 0, 1},
+	}
+}
@@ -17,3 +17,12 @@ func TestAxisAngleRotation(t *testing.T) {
 	verifyTransform(t, aar, 1, 0, 0, 0, 0, 1, 1e-8)
 	verifyTransform(t, aar, 0, 1, 0, 1, 0, 0, 1e-8)
 }
+
+func TestAxisAngleRotationMat4(t *testing.T) {
+	n := NewVec3(1, 2, 3)
+	aar := NewAxisAngleRotation(n.Normalize(n), 1.234)
+	m := aar.Mat4()
+	u := NewVec3(-3, 5, 7)
+	v := aar.Apply(BlankVec3(), u)
+	verifyTransform(t, m, v[0], v[1], v[2], u[0], u[1], u[2], 1e-8)
+}
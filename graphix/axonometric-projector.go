@@ -0,0 +1,61 @@
+package graphix
+
+import "math"
+
+// Defines an axonometric projector, i.e., an orthographic projection after rotating the canonical camera coordinates
+// around a pivot in front of the camera.
+type axonometric struct {
+	rot *Mat3
+	// Inverse rotation.
+	inv *Mat3
+	// Depth of the pivot (i.e., at z=-d0), which stays in place.
+	d0 float64
+}
+
+var (
+	_ Projector   = (*axonometric)(nil)
+	_ Unprojector = (*axonometric)(nil)
+)
+
+// NewAxonometric returns an axonometric projector with respect to the canonical camera position, which first rotates
+// a point around the axis parallel to +y by yaw, then around the axis parallel to +x by pitch, both through the pivot
+// (0,0,-d0), and then projects it orthographically.
+// The depth of the rotated point is kept in the projection, so depth sorting works as in NewOrthographic. Points are
+// clipped once rotated behind the camera, so d0 should exceed the distance of the scene from the pivot.
+func NewAxonometric(yaw, pitch, d0 float64) Projector {
+	sy, cy := math.Sincos(yaw)
+	sp, cp := math.Sincos(pitch)
+	ry := &Mat3{{cy, 0, sy}, {0, 1, 0}, {-sy, 0, cy}}
+	rx := &Mat3{{1, 0, 0}, {0, cp, -sp}, {0, sp, cp}}
+	rot := (&Mat3{}).Mul(rx, ry)
+	return &axonometric{rot: rot, inv: (&Mat3{}).Transpose(rot), d0: d0}
+}
+
+// NewIsometric returns an axonometric projector in which the three canonical camera axes are equally foreshortened,
+// with +y pointing up, +x pointing down-right and +z pointing down-left, see NewAxonometric for d0.
+func NewIsometric(d0 float64) Projector {
+	return NewAxonometric(-math.Pi/4, math.Asin(1/math.Sqrt(3)), d0)
+}
+
+// NewDimetric returns an axonometric projector in which the canonical camera x and z axes are equally foreshortened,
+// and the y axis is foreshortened according to pitch, which tilts the camera to look down on the xz-plane.
+// See NewAxonometric for d0.
+func NewDimetric(pitch, d0 float64) Projector {
+	return NewAxonometric(-math.Pi/4, pitch, d0)
+}
+
+func (*axonometric) NearZClip() float64 { return 0 }
+
+func (ax *axonometric) Project(p *Projection, v *Vec3) *Projection {
+	var u Vec3
+	ax.rot.Apply(&u, NewVec3(v[0], v[1], v[2]+ax.d0))
+	// Use -z as distance since camera is looking at the -z direction.
+	p[0], p[1], p[2] = u[0], u[1], ax.d0-u[2]
+	return p
+}
+
+func (ax *axonometric) Unproject(v *Vec3, p *Projection) *Vec3 {
+	ax.inv.Apply(v, NewVec3(p[0], p[1], ax.d0-p[2]))
+	v[2] -= ax.d0
+	return v
+}
@@ -0,0 +1,42 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsometricProjector(t *testing.T) {
+	iso := NewIsometric(5)
+	assert.Equal(t, 0.0, iso.NearZClip())
+	_, ok := iso.(FarClipper)
+	assert.False(t, ok)
+
+	p := BlankProjection()
+	// The pivot stays in place.
+	assert.Same(t, p, iso.Project(p, NewVec3(0, 0, -5)))
+	assertProjectionEqual(t, 0, 0, 5, p, 1e-8)
+	// The (1,1,1) diagonal from the pivot points straight at the camera.
+	assertProjectionEqual(t, 0, 0, 5-math.Sqrt(3), iso.Project(p, NewVec3(1, 1, -4)), 1e-8)
+
+	// All three axes are equally foreshortened, 120° apart.
+	l, d := math.Sqrt(2.0/3), 5-1/math.Sqrt(3)
+	assertProjectionEqual(t, l*math.Cos(-math.Pi/6), l*math.Sin(-math.Pi/6), d, iso.Project(p, NewVec3(1, 0, -5)), 1e-8)
+	assertProjectionEqual(t, 0, l, d, iso.Project(p, NewVec3(0, 1, -5)), 1e-8)
+	assertProjectionEqual(t, -l*math.Cos(-math.Pi/6), l*math.Sin(-math.Pi/6), d, iso.Project(p, NewVec3(0, 0, -4)), 1e-8)
+	verifyUnproject(t, iso, 1, -2, 3, 1e-8)
+}
+
+func TestDimetricProjector(t *testing.T) {
+	dim := NewDimetric(math.Pi/6, 0)
+	p := BlankProjection()
+	x := *dim.Project(p, NewVec3(1, 0, 0))
+	z := *dim.Project(p, NewVec3(0, 0, 1))
+	y := *dim.Project(p, NewVec3(0, 1, 0))
+	// x and z are equally foreshortened and mirrored, y is foreshortened by cos(pitch).
+	assert.InDelta(t, math.Hypot(x[0], x[1]), math.Hypot(z[0], z[1]), 1e-8)
+	assert.InDelta(t, -x[0], z[0], 1e-8)
+	assertProjectionEqual(t, 0, math.Cos(math.Pi/6), -math.Sin(math.Pi/6), &y, 1e-8)
+	verifyUnproject(t, dim, 1, -2, 3, 1e-8)
+}
@@ -1,6 +1,10 @@
 package graphix
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
 // Camera is a simple wrapper of a view transform, a projector and a screen.
 // This series of transforms takes a point in world coordinates and converts it to a 2D pixel coordinates
@@ -24,6 +28,34 @@ func (cam *Camera) ViewTransform() Transform { return cam.vt }
 func (cam *Camera) Projector() Projector     { return cam.pr }
 func (cam *Camera) Screen() *Screen          { return cam.sc }
 
+// RayAt returns the ray in world coordinates through the screen coordinate (px, py), e.g., (i+.5, j+.5) for the
+// center of pixel (i, j). The ray starts from the near clipping plane and has a normalized direction.
+// An error is returned if the view transform is not an invertible AffineTransform, or if the projector is not an
+// Unprojector.
+func (cam *Camera) RayAt(px, py float64) (*Ray, error) {
+	upr, ok := cam.pr.(Unprojector)
+	if !ok {
+		return nil, errors.New("projector does not support unprojection")
+	}
+	avt, ok := cam.vt.(AffineTransform)
+	if !ok {
+		return nil, errors.New("view transform is not affine")
+	}
+	inv, err := Inverse(avt)
+	if err != nil {
+		return nil, fmt.Errorf("view transform is not invertible: %v", err)
+	}
+	p := cam.sc.Unmap(BlankProjection(), NewProjection(px, py, 0))
+	// Unproject at two depths along the pixel, then convert back to world coordinates.
+	near := math.Max(cam.pr.NearZClip(), 0)
+	p[2] = near
+	origin := inv.Apply(BlankVec3(), upr.Unproject(BlankVec3(), p))
+	p[2] = near + 1
+	dir := inv.Apply(BlankVec3(), upr.Unproject(BlankVec3(), p))
+	dir.Sub(dir, origin)
+	return NewRay(origin, dir.Normalize(dir)), nil
+}
+
 // NewOrtho2DCamera returns a camera in canonical position (sitting at +z, looking at -z with y as up), given the screen mapping.
 func NewOrtho2DCamera(sc *Screen) *Camera {
 	return NewCamera(
@@ -33,6 +65,22 @@ func NewOrtho2DCamera(sc *Screen) *Camera {
 	)
 }
 
+// NewFOVPerspectiveCamera returns a camera with view transform vt and a perspective projector of vertical
+// field of view fovY (in radians) and near/far clipping planes, whose aspect ratio and screen mapping are
+// derived from the screen dimension width and height.
+// An error is returned if the arguments violate the requirements of NewCheckedScreen or NewCheckedFOVPerspective.
+func NewFOVPerspectiveCamera(vt Transform, fovY, near, far float64, width, height int) (*Camera, error) {
+	sc, err := NewCheckedScreen(width, height, -1, -1, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := NewCheckedFOVPerspective(fovY, float64(width)/float64(height), near, far)
+	if err != nil {
+		return nil, err
+	}
+	return NewCamera(vt, pr, sc), nil
+}
+
 // CameraOrbit represents a series of camera configurations.
 type CameraOrbit interface {
 	// Frames returns the total number of frames of camera orbit.
@@ -92,6 +140,39 @@ func NewCircularCameraOrbit(
 	}
 }
 
+// NewCheckedCircularCameraOrbit is like NewCircularCameraOrbit, but returns an error if the arguments
+// violate the requirements documented there or if frames is not positive.
+func NewCheckedCircularCameraOrbit(
+	n *Vec3,
+	pos *Vec3,
+	forward *Vec3,
+	up *Vec3,
+	frames int,
+	angleOffset float64,
+	pr Projector,
+	sc *Screen,
+) (CameraOrbit, error) {
+	if frames <= 0 {
+		return nil, errors.New("frames must be positive")
+	}
+	if pr == nil || sc == nil {
+		return nil, errors.New("projector and screen must not be nil")
+	}
+	if err := checkNormalized("n", n); err != nil {
+		return nil, err
+	}
+	if err := checkFinite("pos", pos); err != nil {
+		return nil, err
+	}
+	if err := checkOrthogonal("n", "pos", n, pos); err != nil {
+		return nil, err
+	}
+	if _, err := NewCheckedViewTransform(pos, forward, up); err != nil {
+		return nil, err
+	}
+	return NewCircularCameraOrbit(n, pos, forward, up, frames, angleOffset, pr, sc), nil
+}
+
 func (cir *circularCameraOrbit) Frames() int { return cir.frames }
 
 func (cir *circularCameraOrbit) GetCamera(i int) *Camera {
@@ -66,3 +66,94 @@ func TestStationaryCamera(t *testing.T) {
 	assert.Equal(t, 5, st.Frames())
 	assert.Same(t, cam, st.GetCamera(15))
 }
+
+func TestCheckedCircularCameraOrbit(t *testing.T) {
+	pr := NewOrthographic()
+	sc := &Screen{}
+	cir, err := NewCheckedCircularCameraOrbit(
+		NewVec3(-1, 0, 0),
+		NewVec3(0, 0, -1),
+		NewVec3(0, 0, 1),
+		NewVec3(1, 0, 0),
+		4,
+		math.Pi/2,
+		pr,
+		sc,
+	)
+	assert.NoError(t, err)
+	assertVec3Equal(t, -1, 1, -1, cir.GetCamera(0).ViewTransform().Apply(BlankVec3(), NewVec3(1, 0, 1)), 1e-8)
+
+	tests := []struct {
+		n, pos, forward, up *Vec3
+		frames              int
+		expErr              string
+	}{
+		{NewVec3(-1, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(1, 0, 0), 0, "frames must be positive"},
+		{NewVec3(-2, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(1, 0, 0), 4, "n must be normalized"},
+		{NewVec3(-1, 0, 0), NewVec3(1, 0, -1), NewVec3(0, 0, 1), NewVec3(1, 0, 0), 4, "n and pos must be orthogonal"},
+		{NewVec3(-1, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 0, 0), NewVec3(1, 0, 0), 4, "forward must be normalized"},
+		{NewVec3(-1, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(0, 0, 1), 4, "forward and up must be orthogonal"},
+	}
+	for _, tt := range tests {
+		_, err := NewCheckedCircularCameraOrbit(tt.n, tt.pos, tt.forward, tt.up, tt.frames, 0, pr, sc)
+		assert.ErrorContains(t, err, tt.expErr)
+	}
+	_, err = NewCheckedCircularCameraOrbit(NewVec3(-1, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(1, 0, 0), 4, 0, nil, sc)
+	assert.ErrorContains(t, err, "projector and screen must not be nil")
+}
+
+func TestFOVPerspectiveCamera(t *testing.T) {
+	vt := NewViewTransform(NewVec3(0, 0, 4), NewVec3(0, 0, -1), NewVec3(0, 1, 0))
+	cam, err := NewFOVPerspectiveCamera(vt, math.Pi/2, .1, 100, 200, 100)
+	assert.NoError(t, err)
+	assert.Same(t, vt, cam.ViewTransform())
+	assert.Equal(t, 100.0, cam.Projector().(FarClipper).FarZClip())
+
+	// The top-right corner of the field of view at the origin maps to the top-right corner of the screen.
+	p := cam.Projector().Project(BlankProjection(), vt.Apply(BlankVec3(), NewVec3(8, 4, 0)))
+	assertProjectionEqual(t, 200, 0, 4, cam.Screen().Map(p, p), 1e-8)
+
+	_, err = NewFOVPerspectiveCamera(vt, math.Pi/2, .1, 100, 200, 0)
+	assert.ErrorContains(t, err, "screen dimension must be positive, got 200x0")
+	_, err = NewFOVPerspectiveCamera(vt, math.Pi, .1, 100, 200, 100)
+	assert.ErrorContains(t, err, "field of view must be in (0,π), got")
+}
+
+func TestCameraRayAt(t *testing.T) {
+	vt, err := LookAt(NewVec3(0, 0, 10), NewVec3(0, 0, 0), NewVec3(0, 1, 0))
+	assert.NoError(t, err)
+	cam, err := NewFOVPerspectiveCamera(vt, math.Pi/2, .1, 100, 200, 100)
+	assert.NoError(t, err)
+
+	// Center of the screen looks straight at the target.
+	r, err := cam.RayAt(100, 50)
+	assert.NoError(t, err)
+	assertVec3Equal(t, 0, 0, 9.9, r.Origin, 1e-8)
+	assertVec3Equal(t, 0, 0, -1, r.Dir, 1e-8)
+
+	// The ray through the projection of a world point passes through it.
+	pos := NewVec3(3, -2, 1)
+	p := cam.Projector().Project(BlankProjection(), vt.Apply(BlankVec3(), pos))
+	cam.Screen().Map(p, p)
+	r, err = cam.RayAt(p[0], p[1])
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, r.Distance(pos), 1e-8)
+
+	// Orthographic rays are parallel to the forward direction.
+	cam = NewOrtho2DCamera(NewScreen(100, 100, -1, -1, 1, 1))
+	r, err = cam.RayAt(75, 25)
+	assert.NoError(t, err)
+	assertVec3Equal(t, .5, .5, 1, r.Origin, 1e-8)
+	assertVec3Equal(t, 0, 0, -1, r.Dir, 1e-8)
+
+	cam = NewCamera(TransformFunc(NewScale(1, 1, 1).Apply), NewOrthographic(), NewScreen(100, 100, -1, -1, 1, 1))
+	_, err = cam.RayAt(0, 0)
+	assert.ErrorContains(t, err, "view transform is not affine")
+	cam = NewCamera(NewScale(0, 1, 1), NewOrthographic(), NewScreen(100, 100, -1, -1, 1, 1))
+	_, err = cam.RayAt(0, 0)
+	assert.ErrorContains(t, err, "view transform is not invertible")
+	// Only the Projector methods of the orthographic projector are promoted.
+	cam = NewCamera(vt, struct{ Projector }{NewOrthographic()}, NewScreen(100, 100, -1, -1, 1, 1))
+	_, err = cam.RayAt(0, 0)
+	assert.ErrorContains(t, err, "projector does not support unprojection")
+}
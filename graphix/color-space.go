@@ -0,0 +1,187 @@
+package graphix
+
+import (
+	"image/color"
+	"math"
+)
+
+// ColorSpace selects the space in which colors are interpolated.
+type ColorSpace int
+
+const (
+	// SRGBSpace interpolates the gamma-encoded sRGB components, as stored in images.
+	SRGBSpace ColorSpace = iota
+	// LinearRGBSpace interpolates the linear-light RGB components, which mixes colors as light does.
+	LinearRGBSpace
+	// OKLabSpace interpolates in the perceptually uniform OKLab space.
+	OKLabSpace
+)
+
+// Decodes a gamma-encoded sRGB component in [0,1] into linear light.
+func srgbToLinear(c float64) float64 {
+	if c <= .04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+.055)/1.055, 2.4)
+}
+
+// Encodes a linear-light component in [0,1] into gamma-encoded sRGB.
+func linearToSRGB(c float64) float64 {
+	if c <= .0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - .055
+}
+
+// Converts linear RGB into OKLab, see https://bottosson.github.io/posts/oklab/
+func linearRGBToOKLab(r, g, b float64) (float64, float64, float64) {
+	l := math.Cbrt(.4122214708*r + .5363325363*g + .0514459929*b)
+	m := math.Cbrt(.2119034982*r + .6806995451*g + .1073969566*b)
+	s := math.Cbrt(.0883024619*r + .2817188376*g + .6299787005*b)
+	return .2104542553*l + .7936177850*m - .0040720468*s,
+		1.9779984951*l - 2.4285922050*m + .4505937099*s,
+		.0259040371*l + .7827717662*m - .8086757660*s
+}
+
+// Converts OKLab into linear RGB, which may be out of the [0,1] gamut.
+func okLabToLinearRGB(ll, a, b float64) (float64, float64, float64) {
+	l := ll + .3963377774*a + .2158037573*b
+	m := ll - .1055613458*a - .0638541728*b
+	s := ll - .0894841775*a - 1.2914855480*b
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return 4.0767416621*l - 3.3077115913*m + .2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - .3413193965*s,
+		-.0041960863*l - .7034186147*m + 1.7076147010*s
+}
+
+// Converts c into the 3 color components in space followed by the straight alpha, all unnormalized floats.
+func colorToSpace(c color.NRGBA64, space ColorSpace) [4]float64 {
+	v := [4]float64{float64(c.R) / max16f, float64(c.G) / max16f, float64(c.B) / max16f, float64(c.A) / max16f}
+	switch space {
+	case LinearRGBSpace:
+		v[0], v[1], v[2] = srgbToLinear(v[0]), srgbToLinear(v[1]), srgbToLinear(v[2])
+	case OKLabSpace:
+		v[0], v[1], v[2] = linearRGBToOKLab(srgbToLinear(v[0]), srgbToLinear(v[1]), srgbToLinear(v[2]))
+	}
+	return v
+}
+
+// The inverse of colorToSpace, clamping out-of-gamut components.
+func spaceToColor(v [4]float64, space ColorSpace) color.NRGBA64 {
+	switch space {
+	case LinearRGBSpace:
+		v[0], v[1], v[2] = linearToSRGB(v[0]), linearToSRGB(v[1]), linearToSRGB(v[2])
+	case OKLabSpace:
+		r, g, b := okLabToLinearRGB(v[0], v[1], v[2])
+		v[0], v[1], v[2] = linearToSRGB(clamp01(r)), linearToSRGB(clamp01(g)), linearToSRGB(clamp01(b))
+	}
+	return color.NRGBA64{R: unit16(v[0]), G: unit16(v[1]), B: unit16(v[2]), A: unit16(v[3])}
+}
+
+func clamp01(x float64) float64 { return math.Max(0, math.Min(x, 1)) }
+
+// Converts x in [0,1] into a 16-bit color component with rounding.
+func unit16(x float64) uint16 { return uint16(math.Round(clamp01(x) * max16f)) }
+
+// OKLab is a color in the perceptually uniform OKLab space, where L is the lightness in [0,1], and A and B are the
+// green-red and blue-yellow axes. The Euclidean distance between two OKLab colors approximates their perceived
+// difference. As a color.Color, an OKLab color is opaque, and clamped into the sRGB gamut.
+type OKLab struct {
+	L, A, B float64
+}
+
+// OKLCH is an OKLab color in polar coordinates, where C is the chroma and H is the hue angle in radians.
+type OKLCH struct {
+	L, C, H float64
+}
+
+// HSL is an sRGB color in hue (in [0,1) around the color wheel starting from red), saturation and lightness,
+// all in [0,1].
+type HSL struct {
+	H, S, L float64
+}
+
+var (
+	_ color.Color = OKLab{}
+	_ color.Color = OKLCH{}
+	_ color.Color = HSL{}
+)
+
+// NewOKLab converts c into OKLab, ignoring its alpha.
+func NewOKLab(c color.Color) OKLab {
+	v := colorToSpace(color.NRGBA64Model.Convert(c).(color.NRGBA64), OKLabSpace)
+	return OKLab{L: v[0], A: v[1], B: v[2]}
+}
+
+// NewOKLCH converts c into OKLCH, ignoring its alpha.
+func NewOKLCH(c color.Color) OKLCH { return NewOKLab(c).OKLCH() }
+
+// NewHSL converts c into HSL, ignoring its alpha.
+func NewHSL(c color.Color) HSL {
+	// See https://en.wikipedia.org/wiki/HSL_and_HSV#From_RGB
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	r, g, b := float64(n.R)/max16f, float64(n.G)/max16f, float64(n.B)/max16f
+	hi, lo := max(r, g, b), min(r, g, b)
+	chroma := hi - lo
+	l := (hi + lo) / 2
+	var h, s float64
+	if chroma > 0 {
+		switch hi {
+		case r:
+			h = math.Mod((g-b)/chroma+6, 6)
+		case g:
+			h = (b-r)/chroma + 2
+		default:
+			h = (r-g)/chroma + 4
+		}
+		h /= 6
+		s = chroma / (1 - math.Abs(2*l-1))
+	}
+	return HSL{H: h, S: s, L: l}
+}
+
+func (c OKLab) RGBA() (r, g, b, a uint32) { return c.NRGBA64().RGBA() }
+
+// NRGBA64 converts c into sRGB, clamping it into the gamut.
+func (c OKLab) NRGBA64() color.NRGBA64 { return spaceToColor([4]float64{c.L, c.A, c.B, 1}, OKLabSpace) }
+
+// InGamut returns whether c is representable in sRGB without clamping.
+func (c OKLab) InGamut() bool {
+	const eps = 1e-6
+	r, g, b := okLabToLinearRGB(c.L, c.A, c.B)
+	return min(r, g, b) >= -eps && max(r, g, b) <= 1+eps
+}
+
+// Distance returns the Euclidean distance between c and d, which approximates their perceived difference.
+func (c OKLab) Distance(d OKLab) float64 {
+	return math.Sqrt((c.L-d.L)*(c.L-d.L) + (c.A-d.A)*(c.A-d.A) + (c.B-d.B)*(c.B-d.B))
+}
+
+// OKLCH converts c into polar coordinates.
+func (c OKLab) OKLCH() OKLCH {
+	return OKLCH{L: c.L, C: math.Hypot(c.A, c.B), H: math.Atan2(c.B, c.A)}
+}
+
+func (c OKLCH) RGBA() (r, g, b, a uint32) { return c.OKLab().RGBA() }
+
+// NRGBA64 converts c into sRGB, clamping it into the gamut.
+func (c OKLCH) NRGBA64() color.NRGBA64 { return c.OKLab().NRGBA64() }
+
+// OKLab converts c into Cartesian coordinates.
+func (c OKLCH) OKLab() OKLab {
+	s, co := math.Sincos(c.H)
+	return OKLab{L: c.L, A: c.C * co, B: c.C * s}
+}
+
+func (c HSL) RGBA() (r, g, b, a uint32) { return c.NRGBA64().RGBA() }
+
+// NRGBA64 converts c into sRGB.
+func (c HSL) NRGBA64() color.NRGBA64 {
+	// See https://en.wikipedia.org/wiki/HSL_and_HSV#HSL_to_RGB_alternative
+	a := c.S * math.Min(c.L, 1-c.L)
+	f := func(n float64) float64 {
+		k := math.Mod(n+c.H*12, 12)
+		return c.L - a*math.Max(-1, min(k-3, 9-k, 1))
+	}
+	return color.NRGBA64{R: unit16(f(0)), G: unit16(f(8)), B: unit16(f(4)), A: math.MaxUint16}
+}
@@ -0,0 +1,87 @@
+package graphix
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSRGBTransfer(t *testing.T) {
+	assert.InDelta(t, 0, srgbToLinear(0), 1e-12)
+	assert.InDelta(t, 1, srgbToLinear(1), 1e-12)
+	assert.InDelta(t, .2140, srgbToLinear(.5), 1e-4)
+	for c := 0.0; c <= 1; c += 1. / 64 {
+		assert.InDelta(t, c, linearToSRGB(srgbToLinear(c)), 1e-12)
+	}
+}
+
+func TestOKLab(t *testing.T) {
+	l, a, b := linearRGBToOKLab(1, 1, 1)
+	assert.InDelta(t, 1, l, 1e-6)
+	assert.InDelta(t, 0, a, 1e-6)
+	assert.InDelta(t, 0, b, 1e-6)
+	// Reference values of sRGB red from https://bottosson.github.io/posts/oklab/
+	l, a, b = linearRGBToOKLab(1, 0, 0)
+	assert.InDelta(t, .62796, l, 1e-4)
+	assert.InDelta(t, .22486, a, 1e-4)
+	assert.InDelta(t, .12585, b, 1e-4)
+	r, g, bb := okLabToLinearRGB(l, a, b)
+	assert.InDelta(t, 1, r, 1e-6)
+	assert.InDelta(t, 0, g, 1e-6)
+	assert.InDelta(t, 0, bb, 1e-6)
+}
+
+func TestColorSpaceRoundTrip(t *testing.T) {
+	c := color.NRGBA64{R: 0x1234, G: 0xfedc, B: 0x8000, A: 0x4000}
+	for _, space := range []ColorSpace{SRGBSpace, LinearRGBSpace, OKLabSpace} {
+		assert.Equal(t, c, spaceToColor(colorToSpace(c, space), space))
+	}
+	// Out-of-gamut OKLab colors are clamped.
+	assert.Equal(t, color.NRGBA64{R: math.MaxUint16, G: 0, B: 0, A: 0}, spaceToColor([4]float64{.7, .4, .2, 0}, OKLabSpace))
+}
+
+func TestOKLabColor(t *testing.T) {
+	red := color.NRGBA64{R: math.MaxUint16, A: math.MaxUint16}
+	lab := NewOKLab(red)
+	assert.InDelta(t, .62796, lab.L, 1e-4)
+	assert.True(t, lab.InGamut())
+	assert.Equal(t, red, lab.NRGBA64())
+	assert.Equal(t, red, color.NRGBA64Model.Convert(lab))
+	assert.False(t, OKLab{L: .5, A: .4}.InGamut())
+	assert.InDelta(t, 5, OKLab{L: 1, A: 3, B: 4}.Distance(OKLab{L: 1}), 1e-12)
+
+	lch := NewOKLCH(red)
+	assert.InDelta(t, .62796, lch.L, 1e-4)
+	assert.InDelta(t, math.Hypot(.22486, .12585), lch.C, 1e-4)
+	assert.InDelta(t, math.Atan2(.12585, .22486), lch.H, 1e-4)
+	back := lch.OKLab()
+	assert.InDelta(t, lab.A, back.A, 1e-12)
+	assert.InDelta(t, lab.B, back.B, 1e-12)
+	assert.Equal(t, red, lch.NRGBA64())
+	assert.Equal(t, red, color.NRGBA64Model.Convert(lch))
+}
+
+func TestHSL(t *testing.T) {
+	cases := []struct {
+		c   color.NRGBA
+		hsl HSL
+	}{
+		{color.NRGBA{R: 255, A: 255}, HSL{H: 0, S: 1, L: .5}},
+		{color.NRGBA{G: 255, A: 255}, HSL{H: 1. / 3, S: 1, L: .5}},
+		{color.NRGBA{B: 255, A: 255}, HSL{H: 2. / 3, S: 1, L: .5}},
+		{color.NRGBA{R: 255, B: 255, A: 255}, HSL{H: 5. / 6, S: 1, L: .5}},
+		{color.NRGBA{R: 255, G: 255, B: 255, A: 255}, HSL{H: 0, S: 0, L: 1}},
+		{color.NRGBA{A: 255}, HSL{H: 0, S: 0, L: 0}},
+		{color.NRGBA{R: 191, G: 64, B: 64, A: 255}, HSL{H: 0, S: .4980, L: .5}},
+	}
+	for _, c := range cases {
+		hsl := NewHSL(c.c)
+		assert.InDelta(t, c.hsl.H, hsl.H, 1e-3)
+		assert.InDelta(t, c.hsl.S, hsl.S, 1e-3)
+		assert.InDelta(t, c.hsl.L, hsl.L, 1e-3)
+		assert.Equal(t, color.NRGBA64Model.Convert(c.c), hsl.NRGBA64())
+		assert.Equal(t, color.NRGBA64Model.Convert(c.c), color.NRGBA64Model.Convert(hsl))
+	}
+}
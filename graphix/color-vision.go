@@ -0,0 +1,49 @@
+package graphix
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// ColorVisionDeficiency is a kind of color blindness.
+type ColorVisionDeficiency int
+
+const (
+	// Protanopia is the absence of long-wavelength (red) cones.
+	Protanopia ColorVisionDeficiency = iota
+	// Deuteranopia is the absence of medium-wavelength (green) cones, the most common deficiency.
+	Deuteranopia
+	// Tritanopia is the absence of short-wavelength (blue) cones.
+	Tritanopia
+)
+
+// Simulation matrices in linear RGB of the full-severity deficiencies, see
+// Machado, Oliveira and Fernandes, "A Physiologically-based Model for Simulation of Color Vision Deficiency", 2009.
+var colorVisionMatrices = map[ColorVisionDeficiency]*Mat3{
+	Protanopia: {
+		{.152286, 1.052583, -.204868},
+		{.114503, .786281, .099216},
+		{-.003882, -.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{.367322, .860646, -.227968},
+		{.280085, .672501, .047413},
+		{-.011820, .042940, .968881},
+	},
+	Tritanopia: {
+		{1.255528, -.076749, -.178779},
+		{-.078411, .930809, .147602},
+		{.004733, .691367, .303900},
+	},
+}
+
+// SimulateColorVision returns the color c as seen by a viewer with the deficiency cvd, keeping the alpha of c.
+func SimulateColorVision(c color.Color, cvd ColorVisionDeficiency) color.NRGBA64 {
+	m, ok := colorVisionMatrices[cvd]
+	if !ok {
+		panic(fmt.Sprintf("unknown color vision deficiency %d", int(cvd)))
+	}
+	v := colorToSpace(color.NRGBA64Model.Convert(c).(color.NRGBA64), LinearRGBSpace)
+	u := m.Apply(BlankVec3(), NewVec3(v[0], v[1], v[2]))
+	return spaceToColor([4]float64{clamp01(u[0]), clamp01(u[1]), clamp01(u[2]), v[3]}, LinearRGBSpace)
+}
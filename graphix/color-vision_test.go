@@ -0,0 +1,36 @@
+package graphix
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateColorVision(t *testing.T) {
+	white := color.NRGBA64{R: 0xffff, G: 0xffff, B: 0xffff, A: 0x8000}
+	red := color.NRGBA{R: 255, A: 255}
+	green := color.NRGBA{G: 255, A: 255}
+	for _, cvd := range []ColorVisionDeficiency{Protanopia, Deuteranopia, Tritanopia} {
+		// Neutral colors and alpha are preserved.
+		s := SimulateColorVision(white, cvd)
+		assert.InDelta(t, 0xffff, float64(s.R), 0x100)
+		assert.InDelta(t, 0xffff, float64(s.G), 0x100)
+		assert.InDelta(t, 0xffff, float64(s.B), 0x100)
+		assert.Equal(t, uint16(0x8000), s.A)
+	}
+	// The hues of red and green are confused with protanopia and deuteranopia, but not with tritanopia.
+	chromaDist := func(c1, c2 color.Color) float64 {
+		l1, l2 := NewOKLab(c1), NewOKLab(c2)
+		return math.Hypot(l1.A-l2.A, l1.B-l2.B)
+	}
+	dist := func(cvd ColorVisionDeficiency) float64 {
+		return chromaDist(SimulateColorVision(red, cvd), SimulateColorVision(green, cvd))
+	}
+	normal := chromaDist(red, green)
+	assert.Less(t, dist(Protanopia), normal/2)
+	assert.Less(t, dist(Deuteranopia), normal/2)
+	assert.Greater(t, dist(Tritanopia), normal/2)
+	assert.Panics(t, func() { SimulateColorVision(red, ColorVisionDeficiency(9)) })
+}
@@ -0,0 +1,94 @@
+package graphix
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ColorbarSettings defines a labelled colorbar, which shows the values mapped by a Colormap.
+type ColorbarSettings struct {
+	// Rectangle of the bar on the image, excluding ticks and labels.
+	Rect     image.Rectangle
+	Colormap *Colormap
+	// Vertical bars show Min at the bottom and Max at the top, with labels on the right.
+	// Horizontal bars show Min on the left and Max on the right, with labels below.
+	Vertical bool
+	// Values mapped to the two ends of the colormap.
+	Min float64
+	Max float64
+	// Number of labelled ticks evenly distributed along the bar including both ends, zero means no ticks.
+	Ticks int
+	// Format of the tick labels for fmt.Sprintf, empty means "%g".
+	Format string
+	// Optional title drawn above the bar.
+	Title string
+	Text  TextSettings
+}
+
+// NewFadingColormap returns the Colormap of the color c whose alpha is scaled by minFading + (maxFading-minFading)*t^gamma,
+// i.e., the fading of visualizer.VisualizeSettings.
+func NewFadingColormap(c color.NRGBA64, minFading, maxFading, gamma float64) *Colormap {
+	faded := func(f float64) color.NRGBA64 {
+		return color.NRGBA64{R: c.R, G: c.G, B: c.B, A: uint16(float64(c.A) * f)}
+	}
+	return newColormap([]color.NRGBA64{faded(minFading), faded(maxFading)}, SRGBSpace, gamma)
+}
+
+// DrawColorbar draws the colorbar defined by settings on dst. The colors of the bar are composited over dst, so that
+// translucent colors, e.g., from NewFadingColormap, appear as they would over the rendered image.
+func DrawColorbar(dst draw.Image, settings ColorbarSettings) error {
+	if settings.Colormap == nil {
+		return errors.New("colormap must not be nil")
+	}
+	if settings.Rect.Empty() {
+		return errors.New("colorbar rectangle must not be empty")
+	}
+	if settings.Ticks == 1 || settings.Ticks < 0 {
+		return fmt.Errorf("invalid number of ticks %v, expecting 0 or at least 2", settings.Ticks)
+	}
+	format := settings.Format
+	if format == "" {
+		format = "%g"
+	}
+	r := settings.Rect
+	length := r.Dx()
+	if settings.Vertical {
+		length = r.Dy()
+	}
+	// Fill the bar one line of pixels at a time, sampling the colormap at the center of each line.
+	for i := range length {
+		c := image.NewUniform(settings.Colormap.At((float64(i) + .5) / float64(length)))
+		line := image.Rect(r.Min.X+i, r.Min.Y, r.Min.X+i+1, r.Max.Y)
+		if settings.Vertical {
+			line = image.Rect(r.Min.X, r.Max.Y-i-1, r.Max.X, r.Max.Y-i)
+		}
+		draw.Draw(dst, line, c, image.Point{}, draw.Over)
+	}
+
+	td := newTextDrawer(&settings.Text)
+	tick := max(int(td.size/3), 2)
+	ink := image.NewUniform(td.color)
+	for k := range settings.Ticks {
+		t := float64(k) / float64(settings.Ticks-1)
+		label := fmt.Sprintf(format, settings.Min+(settings.Max-settings.Min)*t)
+		// Pixel offset of the tick along the bar, keeping the end ticks inside the bar.
+		off := min(int(t*float64(length)), length-1)
+		if settings.Vertical {
+			y := r.Max.Y - 1 - off
+			draw.Draw(dst, image.Rect(r.Max.X, y, r.Max.X+tick, y+1), ink, image.Point{}, draw.Over)
+			td.draw(dst, label, r.Max.X+2*tick, y-td.lineHeight()/2)
+		} else {
+			x := r.Min.X + off
+			draw.Draw(dst, image.Rect(x, r.Max.Y, x+1, r.Max.Y+tick), ink, image.Point{}, draw.Over)
+			td.draw(dst, label, x-td.width(label)/2, r.Max.Y+tick)
+		}
+	}
+	if settings.Title != "" {
+		x := r.Min.X + (r.Dx()-td.width(settings.Title))/2
+		td.draw(dst, settings.Title, x, r.Min.Y-td.lineHeight()-tick)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package graphix
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlackImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	return img
+}
+
+// Returns whether any pixel in r of img is not black.
+func hasInk(img *image.RGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if c := img.RGBAAt(x, y); c.R|c.G|c.B != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDrawColorbar(t *testing.T) {
+	img := newBlackImage(200, 100)
+	cm := Viridis()
+	err := DrawColorbar(img, ColorbarSettings{
+		Rect:     image.Rect(40, 30, 140, 40),
+		Colormap: cm,
+		Min:      0,
+		Max:      2,
+		Ticks:    3,
+		Title:    "|B|",
+	})
+	assert.NoError(t, err)
+	rgba := func(c color.Color) color.RGBA { return color.RGBAModel.Convert(c).(color.RGBA) }
+	assert.Equal(t, rgba(cm.At(.005)), img.RGBAAt(40, 30))
+	assert.Equal(t, rgba(cm.At(.995)), img.RGBAAt(139, 39))
+	assert.Equal(t, rgba(cm.At(.505)), img.RGBAAt(90, 35))
+	// Ticks, labels and title.
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, img.RGBAAt(40, 40))
+	assert.True(t, hasInk(img, image.Rect(30, 44, 50, 60)))
+	assert.True(t, hasInk(img, image.Rect(130, 44, 150, 60)))
+	assert.True(t, hasInk(img, image.Rect(70, 10, 110, 28)))
+	assert.False(t, hasInk(img, image.Rect(0, 0, 30, 100)))
+	assert.False(t, hasInk(img, image.Rect(150, 0, 200, 100)))
+
+	img = newBlackImage(100, 200)
+	err = DrawColorbar(img, ColorbarSettings{
+		Rect:     image.Rect(10, 20, 20, 120),
+		Colormap: cm,
+		Vertical: true,
+		Min:      -1,
+		Max:      1,
+		Ticks:    5,
+		Format:   "%.1f",
+		Text:     TextSettings{Size: 10, Color: color.RGBA{255, 0, 0, 255}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, rgba(cm.At(.005)), img.RGBAAt(15, 119))
+	assert.Equal(t, rgba(cm.At(.995)), img.RGBAAt(15, 20))
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, img.RGBAAt(20, 119))
+	assert.True(t, hasInk(img, image.Rect(24, 60, 60, 80)))
+	assert.False(t, hasInk(img, image.Rect(0, 130, 100, 200)))
+}
+
+func TestFadingColormap(t *testing.T) {
+	cm := NewFadingColormap(color.NRGBA64{R: 0xffff, A: 0x8000}, .2, 1, 2)
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 6553}, cm.At(0))
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 0x8000}, cm.At(1))
+	assert.InDelta(t, 0x8000*(.2+.8*.25), float64(cm.At(.5).A), 1)
+
+	// Translucent colors are composited over the image.
+	img := newBlackImage(10, 10)
+	assert.NoError(t, DrawColorbar(img, ColorbarSettings{Rect: image.Rect(0, 0, 10, 10), Colormap: cm}))
+	c := img.RGBAAt(9, 5)
+	assert.InDelta(t, 255*.5*(.2+.8*.95*.95), c.R, 2)
+	assert.Equal(t, uint8(255), c.A)
+}
+
+func TestDrawColorbarErrors(t *testing.T) {
+	img := newBlackImage(10, 10)
+	assert.Error(t, DrawColorbar(img, ColorbarSettings{Rect: image.Rect(0, 0, 5, 5)}))
+	assert.Error(t, DrawColorbar(img, ColorbarSettings{Colormap: Viridis()}))
+	assert.Error(t, DrawColorbar(img, ColorbarSettings{Rect: image.Rect(0, 0, 5, 5), Colormap: Viridis(), Ticks: 1}))
+}
@@ -0,0 +1,45 @@
+package graphix
+
+// Color stops of the built-in colormaps as 24-bit sRGB, evenly distributed over [0,1].
+
+var viridisStops = []uint32{
+	0x440154, 0x460c5f, 0x47176a, 0x482273, 0x472c7b, 0x453681, 0x423f86, 0x3e4889,
+	0x3b518b, 0x385a8d, 0x34628e, 0x306a8e, 0x2c728e, 0x29798e, 0x26808e, 0x23888d,
+	0x20908c, 0x1f988b, 0x20a088, 0x23a785, 0x29ae81, 0x32b57b, 0x3dbc74, 0x4cc26c,
+	0x5dc962, 0x70cf57, 0x82d44a, 0x97d83d, 0xacdc30, 0xc2e026, 0xd8e21f, 0xece51e,
+	0xfde725,
+}
+
+var magmaStops = []uint32{
+	0x000004, 0x030515, 0x0a0a25, 0x130d35, 0x1d0f45, 0x290f57, 0x360f67, 0x430f75,
+	0x4f137b, 0x5c177f, 0x691c81, 0x762082, 0x832483, 0x8f2982, 0x9b2e80, 0xa9327c,
+	0xb63679, 0xc33b74, 0xd0426f, 0xdb4969, 0xe55263, 0xee5c5e, 0xf5695d, 0xf9775f,
+	0xfc8763, 0xfd9869, 0xffa671, 0xffb57b, 0xfec387, 0xfcd495, 0xfae4a3, 0xf9f2b2,
+	0xfcfdbf,
+}
+
+var infernoStops = []uint32{
+	0x000004, 0x03051c, 0x0b082e, 0x150b3b, 0x210c48, 0x2e0c55, 0x3b0c61, 0x480c6a,
+	0x560f6d, 0x63146f, 0x70196e, 0x7c1d6d, 0x89216b, 0x962667, 0xa22b61, 0xaf315c,
+	0xbb3755, 0xc63e4d, 0xd14644, 0xdb4f3a, 0xe35a31, 0xeb6528, 0xf1711d, 0xf67f12,
+	0xf98d0a, 0xfb9d07, 0xfbab10, 0xfabb1e, 0xf8ca32, 0xf6da48, 0xf5e960, 0xf6f57f,
+	0xfcffa4,
+}
+
+var plasmaStops = []uint32{
+	0x0d0887, 0x1e088e, 0x2f0795, 0x3e049c, 0x4c02a1, 0x5900a5, 0x6500a7, 0x7101a8,
+	0x7d05a7, 0x890ba5, 0x9512a1, 0x9f1a9c, 0xa92296, 0xb22b8f, 0xbb3588, 0xc33e80,
+	0xcb4779, 0xd35071, 0xda5969, 0xe06363, 0xe66c5c, 0xeb7655, 0xf0804e, 0xf58a47,
+	0xf89540, 0xfba039, 0xfdac33, 0xfeb82d, 0xfec428, 0xfdd223, 0xfae01f, 0xf6ed1e,
+	0xf0f921,
+}
+
+var cividisStops = []uint32{
+	0x00224e, 0x032656, 0x082c62, 0x0e326c, 0x163771, 0x223d71, 0x2e436e, 0x3a486c,
+	0x434e6c, 0x4b546c, 0x52596c, 0x595f6d, 0x61646f, 0x676a71, 0x6e7073, 0x767674,
+	0x7d7b76, 0x848178, 0x8c8778, 0x938d78, 0x9b9476, 0xa29a75, 0xaaa072, 0xb3a770,
+	0xbbad6d, 0xc4b469, 0xccbb64, 0xd5c25e, 0xddc958, 0xe6d051, 0xefda47, 0xf8e23e,
+	0xfee838,
+}
+
+var coolWarmStops = []uint32{0x3b4cc0, 0x8db0fe, 0xdddddd, 0xf49a7b, 0xb40426}
@@ -0,0 +1,131 @@
+package graphix
+
+import (
+	"image/color"
+	"math"
+	"slices"
+)
+
+// Colormap maps a value in [0,1] continuously into a color, by interpolating between color stops evenly
+// distributed over [0,1].
+// A Colormap is immutable, methods such as Reversed return a modified copy.
+type Colormap struct {
+	stops []color.NRGBA64
+	// stops converted into space.
+	coords [][4]float64
+	space  ColorSpace
+	gamma  float64
+}
+
+// NewColormap creates a Colormap interpolating between stops in space.
+// Caller is responsible for passing in at least one stop.
+func NewColormap(stops []color.Color, space ColorSpace) *Colormap {
+	ns := make([]color.NRGBA64, len(stops))
+	for i, c := range stops {
+		ns[i] = color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	}
+	return newColormap(ns, space, 1)
+}
+
+func newColormap(stops []color.NRGBA64, space ColorSpace, gamma float64) *Colormap {
+	cm := &Colormap{
+		stops:  stops,
+		coords: make([][4]float64, len(stops)),
+		space:  space,
+		gamma:  gamma,
+	}
+	for i, c := range stops {
+		cm.coords[i] = colorToSpace(c, space)
+	}
+	return cm
+}
+
+// Creates a Colormap from 24-bit opaque sRGB stops.
+func newHexColormap(hexes []uint32, space ColorSpace) *Colormap {
+	stops := make([]color.NRGBA64, len(hexes))
+	for i, h := range hexes {
+		r, g, b := uint16(h>>16&0xff), uint16(h>>8&0xff), uint16(h&0xff)
+		stops[i] = color.NRGBA64{R: r<<8 | r, G: g<<8 | g, B: b<<8 | b, A: math.MaxUint16}
+	}
+	return newColormap(stops, space, 1)
+}
+
+// At returns the color at t, which is clamped into [0,1].
+func (cm *Colormap) At(t float64) color.NRGBA64 {
+	if !(t > 0) {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	if cm.gamma != 1 {
+		t = math.Pow(t, cm.gamma)
+	}
+	n := len(cm.stops)
+	x := t * float64(n-1)
+	i := min(int(x), max(n-2, 0))
+	s := x - float64(i)
+	// Return the stops exactly.
+	switch s {
+	case 0:
+		return cm.stops[i]
+	case 1:
+		return cm.stops[i+1]
+	}
+	a, b := &cm.coords[i], &cm.coords[i+1]
+	var v [4]float64
+	for j := range v {
+		v[j] = a[j] + (b[j]-a[j])*s
+	}
+	return spaceToColor(v, cm.space)
+}
+
+// Colors returns n colors evenly sampled from the colormap, from t=0 to t=1.
+func (cm *Colormap) Colors(n int) []color.NRGBA64 {
+	colors := make([]color.NRGBA64, n)
+	for i := range colors {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		colors[i] = cm.At(t)
+	}
+	return colors
+}
+
+// Stops returns a copy of the color stops of the colormap.
+func (cm *Colormap) Stops() []color.NRGBA64 { return slices.Clone(cm.stops) }
+
+func (cm *Colormap) Space() ColorSpace { return cm.space }
+func (cm *Colormap) Gamma() float64    { return cm.gamma }
+
+// Reversed returns the colormap with the order of the color stops reversed.
+func (cm *Colormap) Reversed() *Colormap {
+	stops := slices.Clone(cm.stops)
+	slices.Reverse(stops)
+	return newColormap(stops, cm.space, cm.gamma)
+}
+
+// WithGamma returns the colormap which looks up t^gamma for t. A gamma larger than 1 thus expands the lower end of
+// the colormap over more values.
+func (cm *Colormap) WithGamma(gamma float64) *Colormap {
+	return newColormap(cm.stops, cm.space, gamma)
+}
+
+// WithSpace returns the colormap interpolating between the same color stops in space.
+func (cm *Colormap) WithSpace(space ColorSpace) *Colormap {
+	return newColormap(cm.stops, space, cm.gamma)
+}
+
+// The following built-in colormaps reproduce matplotlib's colormaps of the same names within a few 8-bit levels.
+// They are perceptually uniform, and readable by viewers with color vision deficiency.
+
+func Viridis() *Colormap { return newHexColormap(viridisStops, SRGBSpace) }
+func Magma() *Colormap   { return newHexColormap(magmaStops, SRGBSpace) }
+func Inferno() *Colormap { return newHexColormap(infernoStops, SRGBSpace) }
+func Plasma() *Colormap  { return newHexColormap(plasmaStops, SRGBSpace) }
+
+// Cividis is optimized for viewers with red-green color vision deficiency.
+func Cividis() *Colormap { return newHexColormap(cividisStops, SRGBSpace) }
+
+// CoolWarm returns the diverging blue-white-red colormap, suitable for values of either sign around 0.5.
+func CoolWarm() *Colormap { return newHexColormap(coolWarmStops, OKLabSpace) }
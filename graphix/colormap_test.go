@@ -0,0 +1,83 @@
+package graphix
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColormapAt(t *testing.T) {
+	black := color.NRGBA64{A: 0xffff}
+	white := color.NRGBA64{R: 0xffff, G: 0xffff, B: 0xffff, A: 0xffff}
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	cm := NewColormap([]color.Color{color.Black, color.White, red}, SRGBSpace)
+	assert.Equal(t, black, cm.At(0))
+	assert.Equal(t, white, cm.At(.5))
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 0xffff}, cm.At(1))
+	assert.Equal(t, color.NRGBA64{R: 0x8000, G: 0x8000, B: 0x8000, A: 0xffff}, cm.At(.25))
+	assert.Equal(t, color.NRGBA64{R: 0xffff, G: 0x8000, B: 0x8000, A: 0xffff}, cm.At(.75))
+	// Clamped.
+	assert.Equal(t, black, cm.At(-1))
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 0xffff}, cm.At(2))
+
+	// Mid-gray in linear light is brighter when encoded.
+	lin := cm.WithSpace(LinearRGBSpace)
+	assert.Equal(t, LinearRGBSpace, lin.Space())
+	assert.Equal(t, white, lin.At(.5))
+	assert.InDelta(t, .7354*max16f, float64(lin.At(.25).R), 5)
+	// Interpolation in OKLab keeps gray neutral.
+	ok := cm.WithSpace(OKLabSpace).At(.25)
+	assert.Equal(t, ok.R, ok.G)
+	assert.InDelta(t, ok.R, ok.B, 1)
+
+	// Reversed and gamma.
+	rev := cm.Reversed()
+	assert.Equal(t, cm.At(.8), rev.At(.2))
+	g := cm.WithGamma(2)
+	assert.Equal(t, 2., g.Gamma())
+	assert.Equal(t, cm.At(.25), g.At(.5))
+	assert.Equal(t, cm.At(.09), g.WithSpace(SRGBSpace).At(.3))
+	assert.Equal(t, 1., cm.Gamma())
+
+	// A single stop.
+	single := NewColormap([]color.Color{red}, OKLabSpace)
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 0xffff}, single.At(.3))
+}
+
+func TestColormapColors(t *testing.T) {
+	cm := NewColormap([]color.Color{color.Black, color.White}, SRGBSpace)
+	colors := cm.Colors(3)
+	assert.Equal(t, []color.NRGBA64{cm.At(0), cm.At(.5), cm.At(1)}, colors)
+	assert.Equal(t, []color.NRGBA64{cm.At(0)}, cm.Colors(1))
+	stops := cm.Stops()
+	stops[0] = color.NRGBA64{}
+	assert.Equal(t, color.NRGBA64{A: 0xffff}, cm.At(0))
+}
+
+func TestBuiltinColormaps(t *testing.T) {
+	rgb := func(c color.NRGBA64) [3]uint8 { return [3]uint8{uint8(c.R >> 8), uint8(c.G >> 8), uint8(c.B >> 8)} }
+	assert.Equal(t, [3]uint8{0x44, 0x01, 0x54}, rgb(Viridis().At(0)))
+	assert.Equal(t, [3]uint8{0xfd, 0xe7, 0x25}, rgb(Viridis().At(1)))
+	assert.Equal(t, [3]uint8{0x0d, 0x08, 0x87}, rgb(Plasma().At(0)))
+	assert.Equal(t, [3]uint8{0xfc, 0xfd, 0xbf}, rgb(Magma().At(1)))
+	assert.Equal(t, [3]uint8{0xfc, 0xff, 0xa4}, rgb(Inferno().At(1)))
+	assert.Equal(t, [3]uint8{0x00, 0x22, 0x4e}, rgb(Cividis().At(0)))
+	assert.Equal(t, [3]uint8{0xdd, 0xdd, 0xdd}, rgb(CoolWarm().At(.5)))
+
+	// Lightness of the sequential colormaps increases monotonically.
+	for _, cm := range []*Colormap{Viridis(), Magma(), Inferno(), Plasma(), Cividis()} {
+		prev := -1.0
+		for i := range 101 {
+			l := colorToSpace(cm.At(float64(i)/100), OKLabSpace)[0]
+			assert.Greater(t, l, prev)
+			prev = l
+		}
+	}
+	// The diverging colormap is lightest in the middle.
+	l := func(t float64) float64 { return colorToSpace(CoolWarm().At(t), OKLabSpace)[0] }
+	assert.Greater(t, l(.5), l(.25))
+	assert.Greater(t, l(.5), l(.75))
+	assert.Greater(t, l(.25), l(0))
+	assert.Greater(t, l(.75), l(1))
+}
@@ -0,0 +1,113 @@
+package graphix
+
+import "math"
+
+// CurvedProjector is implemented by Projectors which do not map straight lines in canonical camera coordinates
+// into straight lines on the projection plane. Rasterizers shall subdivide segments projected by a CurvedProjector
+// so that each piece is close enough to a straight line.
+type CurvedProjector interface {
+	Projector
+	// Discontinuous returns whether the projections p1 and p2 of the endpoints of a segment lie across a
+	// discontinuity of the projection (e.g., the ±π seam of an equirectangular projection), in which case the
+	// segment cannot be stroked as a single piece.
+	Discontinuous(p1, p2 *Projection) bool
+}
+
+// Defines an equidistant fisheye projector.
+type fisheye struct {
+	// Radius on the projection plane per unit of angle from the forward direction.
+	scale float64
+	near  float64
+	far   float64
+}
+
+var (
+	_ CurvedProjector      = (*fisheye)(nil)
+	_ FarClipper           = (*fisheye)(nil)
+	_ PerspectiveProjector = (*fisheye)(nil)
+	_ Unprojector          = (*fisheye)(nil)
+)
+
+// NewFisheye returns an equidistant fisheye projector with respect to the canonical camera position, where
+// a point at angle θ from the forward direction is projected at radius θ/(fov/2) on the projection plane.
+// The field of view fov (in radians, up to 2π) thus covers the unit disc, see NewFOVScreen.
+// The z-distance kept in the projection is the distance from the camera, which is clipped by near and far.
+func NewFisheye(fov, near, far float64) CurvedProjector {
+	return &fisheye{scale: 2 / fov, near: near, far: far}
+}
+
+func (fe *fisheye) NearZClip() float64 { return fe.near }
+func (fe *fisheye) FarZClip() float64  { return fe.far }
+func (*fisheye) Perspective() bool     { return true }
+
+func (fe *fisheye) Project(p *Projection, v *Vec3) *Projection {
+	rho := math.Hypot(v[0], v[1])
+	r := math.Atan2(rho, -v[2]) * fe.scale
+	if rho == 0 {
+		p[0], p[1] = r, 0
+	} else {
+		p[0], p[1] = r*v[0]/rho, r*v[1]/rho
+	}
+	p[2] = v.Norm()
+	return p
+}
+
+func (fe *fisheye) Unproject(v *Vec3, p *Projection) *Vec3 {
+	r := math.Hypot(p[0], p[1])
+	st, ct := math.Sincos(r / fe.scale)
+	v[0], v[1], v[2] = st*p[2], 0, -ct*p[2]
+	if r != 0 {
+		v[0], v[1] = st*p[2]*p[0]/r, st*p[2]*p[1]/r
+	}
+	return v
+}
+
+// Discontinuous returns true if the segment jumps by more than the radius of the backward direction, which only
+// happens when passing behind the camera.
+func (fe *fisheye) Discontinuous(p1, p2 *Projection) bool {
+	return math.Hypot(p1[0]-p2[0], p1[1]-p2[1]) > math.Pi*fe.scale
+}
+
+// Defines an equirectangular (longitude/latitude) projector.
+type equirectangular struct {
+	near float64
+	far  float64
+}
+
+var (
+	_ CurvedProjector      = (*equirectangular)(nil)
+	_ FarClipper           = (*equirectangular)(nil)
+	_ PerspectiveProjector = (*equirectangular)(nil)
+	_ Unprojector          = (*equirectangular)(nil)
+)
+
+// NewEquirectangular returns an equirectangular projector with respect to the canonical camera position, covering
+// the full sphere of directions around the camera. The longitude in [-π,π] (0 for the forward direction, increasing
+// towards +x) and the latitude in [-π/2,π/2] (increasing towards +y) are scaled into [-1,1], see NewFOVScreen.
+// The z-distance kept in the projection is the distance from the camera, which is clipped by near and far.
+func NewEquirectangular(near, far float64) CurvedProjector {
+	return &equirectangular{near: near, far: far}
+}
+
+func (eq *equirectangular) NearZClip() float64 { return eq.near }
+func (eq *equirectangular) FarZClip() float64  { return eq.far }
+func (*equirectangular) Perspective() bool     { return true }
+
+func (eq *equirectangular) Project(p *Projection, v *Vec3) *Projection {
+	p[0] = math.Atan2(v[0], -v[2]) / math.Pi
+	p[1] = math.Atan2(v[1], math.Hypot(v[0], v[2])) / (math.Pi / 2)
+	p[2] = v.Norm()
+	return p
+}
+
+func (eq *equirectangular) Unproject(v *Vec3, p *Projection) *Vec3 {
+	slon, clon := math.Sincos(p[0] * math.Pi)
+	slat, clat := math.Sincos(p[1] * math.Pi / 2)
+	v[0], v[1], v[2] = clat*slon*p[2], slat*p[2], -clat*clon*p[2]
+	return v
+}
+
+// Discontinuous returns true if the segment crosses the seam at longitude ±π behind the camera.
+func (eq *equirectangular) Discontinuous(p1, p2 *Projection) bool {
+	return math.Abs(p1[0]-p2[0]) > 1
+}
@@ -0,0 +1,59 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFisheyeProjector(t *testing.T) {
+	fe := NewFisheye(math.Pi, .1, 100)
+	assert.Equal(t, .1, fe.NearZClip())
+	assert.Equal(t, 100.0, fe.(FarClipper).FarZClip())
+
+	p := BlankProjection()
+	assert.Same(t, p, fe.Project(p, NewVec3(0, 0, -2)))
+	assertProjectionEqual(t, 0, 0, 2, p, 1e-8)
+	// 90° off the forward direction is on the unit circle.
+	assertProjectionEqual(t, 1, 0, 3, fe.Project(p, NewVec3(3, 0, 0)), 1e-8)
+	assertProjectionEqual(t, 0, -1, 3, fe.Project(p, NewVec3(0, -3, 0)), 1e-8)
+	// 45° off the forward direction is halfway.
+	assertProjectionEqual(t, .5/math.Sqrt(2), .5/math.Sqrt(2), 2, fe.Project(p, NewVec3(1, 1, -math.Sqrt(2))), 1e-8)
+	verifyUnproject(t, fe, 1, -2, 3, 1e-8)
+	verifyUnproject(t, fe, 0, 0, -3, 1e-8)
+	verifyUnproject(t, fe, -1, 2, -3, 1e-8)
+
+	// Behind the camera.
+	q := *fe.Project(BlankProjection(), NewVec3(.01, 0, 1))
+	r := *fe.Project(BlankProjection(), NewVec3(-.01, 0, 1))
+	assert.True(t, fe.Discontinuous(&q, &r))
+	r = *fe.Project(BlankProjection(), NewVec3(.01, .01, 1))
+	assert.False(t, fe.Discontinuous(&q, &r))
+}
+
+func TestEquirectangularProjector(t *testing.T) {
+	eq := NewEquirectangular(.1, 100)
+	assert.Equal(t, .1, eq.NearZClip())
+	assert.Equal(t, 100.0, eq.(FarClipper).FarZClip())
+
+	p := BlankProjection()
+	assert.Same(t, p, eq.Project(p, NewVec3(0, 0, -2)))
+	assertProjectionEqual(t, 0, 0, 2, p, 1e-8)
+	assertProjectionEqual(t, .5, 0, 2, eq.Project(p, NewVec3(2, 0, 0)), 1e-8)
+	assertProjectionEqual(t, -.5, 0, 2, eq.Project(p, NewVec3(-2, 0, 0)), 1e-8)
+	// Longitude is arbitrary at the poles.
+	eq.Project(p, NewVec3(0, 2, 0))
+	assert.InDelta(t, 1, p[1], 1e-8)
+	assert.InDelta(t, 2, p[2], 1e-8)
+	assertProjectionEqual(t, .25, .5, 2, eq.Project(p, NewVec3(1, math.Sqrt(2), -1)), 1e-8)
+	verifyUnproject(t, eq, 1, -2, 3, 1e-8)
+	verifyUnproject(t, eq, -1, 2, -3, 1e-8)
+
+	// Across the seam behind the camera.
+	q := *eq.Project(BlankProjection(), NewVec3(.01, 0, 1))
+	r := *eq.Project(BlankProjection(), NewVec3(-.01, 0, 1))
+	assert.True(t, eq.Discontinuous(&q, &r))
+	r = *eq.Project(BlankProjection(), NewVec3(.01, 0, -1))
+	assert.False(t, eq.Discontinuous(&q, &r))
+}
@@ -0,0 +1,75 @@
+package graphix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Easing remaps a normalized time t∈[0,1] into the normalized progress of an animation.
+// Implementations should satisfy Ease(0)=0 and Ease(1)=1.
+type Easing interface {
+	Ease(t float64) float64
+}
+
+type EasingFunc func(t float64) float64
+
+var _ Easing = (EasingFunc)(nil)
+
+func (ef EasingFunc) Ease(t float64) float64 { return ef(t) }
+
+// Built-in easing curves.
+type easing int
+
+const (
+	linearEasing easing = iota
+	inQuadEasing
+	outQuadEasing
+	inOutQuadEasing
+	inOutCubicEasing
+	inOutSineEasing
+)
+
+var _ Easing = linearEasing
+
+// LinearEasing returns the identity easing.
+func LinearEasing() Easing { return linearEasing }
+
+// EaseInQuad returns the easing accelerating from zero velocity.
+func EaseInQuad() Easing { return inQuadEasing }
+
+// EaseOutQuad returns the easing decelerating to zero velocity.
+func EaseOutQuad() Easing { return outQuadEasing }
+
+// EaseInOutQuad returns the easing accelerating until halfway then decelerating, with quadratic curves.
+func EaseInOutQuad() Easing { return inOutQuadEasing }
+
+// EaseInOutCubic returns the easing accelerating until halfway then decelerating, with cubic curves.
+func EaseInOutCubic() Easing { return inOutCubicEasing }
+
+// EaseInOutSine returns the easing accelerating until halfway then decelerating, with a half cosine wave.
+func EaseInOutSine() Easing { return inOutSineEasing }
+
+func (e easing) Ease(t float64) float64 {
+	switch e {
+	case inQuadEasing:
+		return t * t
+	case outQuadEasing:
+		return t * (2 - t)
+	case inOutQuadEasing:
+		if t < .5 {
+			return 2 * t * t
+		}
+		return 1 - 2*(1-t)*(1-t)
+	case inOutCubicEasing:
+		if t < .5 {
+			return 4 * t * t * t
+		}
+		return 1 - 4*(1-t)*(1-t)*(1-t)
+	case inOutSineEasing:
+		return (1 - math.Cos(math.Pi*t)) / 2
+	case linearEasing:
+		return t
+	default:
+		panic(fmt.Sprintf("unknown easing %d", int(e)))
+	}
+}
@@ -0,0 +1,38 @@
+package graphix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasing(t *testing.T) {
+	easings := []Easing{
+		LinearEasing(),
+		EaseInQuad(),
+		EaseOutQuad(),
+		EaseInOutQuad(),
+		EaseInOutCubic(),
+		EaseInOutSine(),
+		EasingFunc(func(t float64) float64 { return t * t * t }),
+	}
+	for _, e := range easings {
+		assert.InDelta(t, 0, e.Ease(0), 1e-12)
+		assert.InDelta(t, 1, e.Ease(1), 1e-12)
+		// Monotonic.
+		prev := 0.0
+		for i := 1; i <= 100; i++ {
+			v := e.Ease(float64(i) / 100)
+			assert.GreaterOrEqual(t, v, prev)
+			prev = v
+		}
+	}
+	assert.InDelta(t, .3, LinearEasing().Ease(.3), 1e-12)
+	assert.InDelta(t, .09, EaseInQuad().Ease(.3), 1e-12)
+	assert.InDelta(t, .51, EaseOutQuad().Ease(.3), 1e-12)
+	// Symmetric in-out easings pass through the middle.
+	assert.InDelta(t, .5, EaseInOutQuad().Ease(.5), 1e-12)
+	assert.InDelta(t, .5, EaseInOutCubic().Ease(.5), 1e-12)
+	assert.InDelta(t, .5, EaseInOutSine().Ease(.5), 1e-12)
+	assert.InDelta(t, .108, EaseInOutCubic().Ease(.3), 1e-12)
+}
@@ -0,0 +1,121 @@
+package graphix
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+)
+
+// FloatImage is an in-memory image with float32 red, green, blue and alpha channels, nominally within [0,1].
+// Colors are premultiplied by alpha unless Straight is set.
+type FloatImage struct {
+	// Channels of the pixel at (x,y) start at Pix[(y-Rect.Min.Y)*Stride+(x-Rect.Min.X)*4].
+	Pix    []float32
+	Stride int
+	Rect   image.Rectangle
+	// Whether colors are not premultiplied by alpha.
+	Straight bool
+}
+
+var _ draw.Image = (*FloatImage)(nil)
+
+// NewFloatImage creates a transparent FloatImage with premultiplied colors and the given bounds.
+func NewFloatImage(r image.Rectangle) *FloatImage {
+	return &FloatImage{
+		Pix:    make([]float32, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (fi *FloatImage) ColorModel() color.Model {
+	if fi.Straight {
+		return color.NRGBA64Model
+	}
+	return color.RGBA64Model
+}
+
+func (fi *FloatImage) Bounds() image.Rectangle { return fi.Rect }
+
+// PixOffset returns the index of the first channel of the pixel at (x,y) in Pix.
+func (fi *FloatImage) PixOffset(x, y int) int {
+	return (y-fi.Rect.Min.Y)*fi.Stride + (x-fi.Rect.Min.X)*4
+}
+
+// At returns the color of the pixel at (x,y), with the channels clamped into [0,1] and quantized to 16 bits.
+func (fi *FloatImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(fi.Rect)) {
+		if fi.Straight {
+			return color.NRGBA64{}
+		}
+		return color.RGBA64{}
+	}
+	i := fi.PixOffset(x, y)
+	s := fi.Pix[i : i+4 : i+4]
+	a := unit16(float64(s[3]))
+	if fi.Straight {
+		return color.NRGBA64{unit16(float64(s[0])), unit16(float64(s[1])), unit16(float64(s[2])), a}
+	}
+	// Keep the color premultiplied after clamping.
+	return color.RGBA64{min(unit16(float64(s[0])), a), min(unit16(float64(s[1])), a), min(unit16(float64(s[2])), a), a}
+}
+
+// Set sets the pixel at (x,y) to c.
+func (fi *FloatImage) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(fi.Rect)) {
+		return
+	}
+	i := fi.PixOffset(x, y)
+	s := fi.Pix[i : i+4 : i+4]
+	if fi.Straight {
+		n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+		s[0], s[1], s[2], s[3] = float32(n.R)/math.MaxUint16, float32(n.G)/math.MaxUint16,
+			float32(n.B)/math.MaxUint16, float32(n.A)/math.MaxUint16
+		return
+	}
+	r, g, b, a := c.RGBA()
+	s[0], s[1], s[2], s[3] = float32(r)/math.MaxUint16, float32(g)/math.MaxUint16,
+		float32(b)/math.MaxUint16, float32(a)/math.MaxUint16
+}
+
+// EncodePFM writes img to w in the little-endian color Portable FloatMap format.
+// PFM has no alpha channel, so the colors are written as composited over black. The channels of a FloatImage are
+// written without clamping or quantization.
+func EncodePFM(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	bw := bufio.NewWriter(w)
+	// A negative scale denotes little-endian samples.
+	if _, err := fmt.Fprintf(bw, "PF\n%v %v\n-1.0\n", b.Dx(), b.Dy()); err != nil {
+		return err
+	}
+	fi, _ := img.(*FloatImage)
+	row := make([]byte, 12*b.Dx())
+	// Rows are stored from bottom to top.
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var rgb [3]float32
+			if fi != nil {
+				s := fi.Pix[fi.PixOffset(x, y):]
+				rgb = [3]float32{s[0], s[1], s[2]}
+				if fi.Straight {
+					rgb = [3]float32{s[0] * s[3], s[1] * s[3], s[2] * s[3]}
+				}
+			} else {
+				r, g, b, _ := img.At(x, y).RGBA()
+				rgb = [3]float32{float32(r) / math.MaxUint16, float32(g) / math.MaxUint16, float32(b) / math.MaxUint16}
+			}
+			for c, v := range rgb {
+				binary.LittleEndian.PutUint32(row[12*(x-b.Min.X)+4*c:], math.Float32bits(v))
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,83 @@
+package graphix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatImage(t *testing.T) {
+	fi := NewFloatImage(image.Rect(1, 2, 4, 4))
+	assert.Equal(t, image.Rect(1, 2, 4, 4), fi.Bounds())
+	assert.Equal(t, color.RGBA64Model, fi.ColorModel())
+	assert.Len(t, fi.Pix, 24)
+	assert.Equal(t, 12, fi.Stride)
+	assert.Equal(t, 16, fi.PixOffset(2, 3))
+
+	fi.Set(2, 3, color.NRGBA{R: 0xff, G: 0x80, A: 0x80})
+	assert.InDeltaSlice(t, []float32{.502, .251, 0, .502}, fi.Pix[16:20], 1e-3)
+	assert.Equal(t, color.RGBA64{R: 0x8080, G: 0x4080, A: 0x8080}, fi.At(2, 3))
+	// Out of range channels are clamped, and colors are kept premultiplied.
+	copy(fi.Pix[:4], []float32{1.5, -.5, .5, .25})
+	assert.Equal(t, color.RGBA64{R: 0x4000, B: 0x4000, A: 0x4000}, fi.At(1, 2))
+	// Pixels out of bounds are transparent and not set.
+	assert.Equal(t, color.RGBA64{}, fi.At(0, 0))
+	fi.Set(0, 0, color.White)
+
+	fi.Straight = true
+	assert.Equal(t, color.NRGBA64Model, fi.ColorModel())
+	fi.Set(3, 3, color.NRGBA{R: 0xff, A: 0x80})
+	assert.Equal(t, []float32{1, 0, 0, float32(0x8080) / math.MaxUint16}, fi.Pix[20:24])
+	assert.Equal(t, color.NRGBA64{R: 0xffff, A: 0x8080}, fi.At(3, 3))
+}
+
+func TestEncodePFM(t *testing.T) {
+	fi := NewFloatImage(image.Rect(0, 0, 2, 2))
+	copy(fi.Pix, []float32{
+		1.5, .25, 0, 1, 0, 0, 0, 0,
+		.5, .5, .5, .5, 0, 1, 0, 1,
+	})
+	readFloats := func(data []byte) []float32 {
+		fs := make([]float32, len(data)/4)
+		for i := range fs {
+			fs[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+		return fs
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, EncodePFM(&buf, fi))
+	header := "PF\n2 2\n-1.0\n"
+	assert.Equal(t, header, buf.String()[:len(header)])
+	// Bottom row first, and values are not clamped.
+	assert.Equal(t, []float32{.5, .5, .5, 0, 1, 0, 1.5, .25, 0, 0, 0, 0}, readFloats(buf.Bytes()[len(header):]))
+
+	// Straight colors are composited over black.
+	fi.Straight = true
+	buf.Reset()
+	assert.NoError(t, EncodePFM(&buf, fi))
+	assert.Equal(t, []float32{.25, .25, .25, 0, 1, 0, 1.5, .25, 0, 0, 0, 0}, readFloats(buf.Bytes()[len(header):]))
+
+	// Other images.
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{R: 0xff, G: 0x33, A: 0xff})
+	buf.Reset()
+	assert.NoError(t, EncodePFM(&buf, img))
+	assert.Equal(t, []float32{1, .2, 0}, readFloats(buf.Bytes()[len("PF\n1 1\n-1.0\n"):]))
+}
+
+func TestFloatImagePNG(t *testing.T) {
+	fi := NewFloatImage(image.Rect(0, 0, 1, 1))
+	copy(fi.Pix, []float32{.1, .2, .3, 1})
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, fi))
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	// Encoded with 16 bits per channel.
+	assert.Equal(t, color.RGBA64{R: 6554, G: 13107, B: 19661, A: 0xffff}, img.At(0, 0))
+}
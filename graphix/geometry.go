@@ -0,0 +1,113 @@
+package graphix
+
+import "math"
+
+// Volume defines a closed region of 3D space bounded by a surface.
+type Volume interface {
+	// Contains returns whether p is inside the volume or on its boundary.
+	Contains(p *Vec3) bool
+	// SignedDistance returns the distance from p to the boundary, which is negative when p is inside the volume.
+	SignedDistance(p *Vec3) float64
+	// ClosestPoint stores the point on the boundary closest to p into v then returns v.
+	ClosestPoint(v, p *Vec3) *Vec3
+	// IntersectRay returns the smallest ray parameter t≥0 at which r crosses the boundary,
+	// or false if there is no such crossing.
+	IntersectRay(r *Ray) (float64, bool)
+}
+
+// Ray represents the half line Origin+t*Dir for t≥0. Dir does not need to be normalized,
+// in which case t is measured in units of Dir's length.
+type Ray struct {
+	Origin *Vec3
+	Dir    *Vec3
+}
+
+// NewRay creates a Ray starting from origin along dir.
+func NewRay(origin, dir *Vec3) *Ray {
+	return &Ray{Origin: NewCopyVec3(origin), Dir: NewCopyVec3(dir)}
+}
+
+// At stores the point at parameter t along the ray into v then returns v.
+func (r *Ray) At(v *Vec3, t float64) *Vec3 {
+	v[0] = r.Origin[0] + t*r.Dir[0]
+	v[1] = r.Origin[1] + t*r.Dir[1]
+	v[2] = r.Origin[2] + t*r.Dir[2]
+	return v
+}
+
+// ClosestT returns the parameter of the point on the ray closest to p.
+func (r *Ray) ClosestT(p *Vec3) float64 {
+	dd := r.Dir.Dot(r.Dir)
+	if dd == 0 {
+		return 0
+	}
+	var d Vec3
+	return math.Max(0, d.Sub(p, r.Origin).Dot(r.Dir)/dd)
+}
+
+// ClosestPoint stores the point on the ray closest to p into v then returns v.
+func (r *Ray) ClosestPoint(v, p *Vec3) *Vec3 { return r.At(v, r.ClosestT(p)) }
+
+// Distance returns the distance from p to the ray.
+func (r *Ray) Distance(p *Vec3) float64 {
+	var c Vec3
+	return c.Sub(p, r.ClosestPoint(&c, p)).Norm()
+}
+
+// Segment represents the line segment from A to B.
+type Segment struct {
+	A *Vec3
+	B *Vec3
+}
+
+// NewSegment creates a Segment from a to b.
+func NewSegment(a, b *Vec3) *Segment {
+	return &Segment{A: NewCopyVec3(a), B: NewCopyVec3(b)}
+}
+
+// Ray returns the ray from A through B, whose parameter t∈[0,1] covers the segment.
+func (s *Segment) Ray() *Ray {
+	return &Ray{Origin: NewCopyVec3(s.A), Dir: BlankVec3().Sub(s.B, s.A)}
+}
+
+// Length returns the length of the segment.
+func (s *Segment) Length() float64 {
+	var d Vec3
+	return d.Sub(s.B, s.A).Norm()
+}
+
+// At stores the point at parameter t∈[0,1] along the segment into v then returns v.
+func (s *Segment) At(v *Vec3, t float64) *Vec3 {
+	v[0] = s.A[0] + t*(s.B[0]-s.A[0])
+	v[1] = s.A[1] + t*(s.B[1]-s.A[1])
+	v[2] = s.A[2] + t*(s.B[2]-s.A[2])
+	return v
+}
+
+// ClosestPoint stores the point on the segment closest to p into v then returns v.
+func (s *Segment) ClosestPoint(v, p *Vec3) *Vec3 {
+	var d, w Vec3
+	d.Sub(s.B, s.A)
+	dd := d.Dot(&d)
+	if dd == 0 {
+		return v.Copy(s.A)
+	}
+	t := min(max(w.Sub(p, s.A).Dot(&d)/dd, 0), 1)
+	return s.At(v, t)
+}
+
+// Distance returns the distance from p to the segment.
+func (s *Segment) Distance(p *Vec3) float64 {
+	var c Vec3
+	return c.Sub(p, s.ClosestPoint(&c, p)).Norm()
+}
+
+// Intersect returns the smallest parameter t∈[0,1] at which the segment crosses the boundary of vol,
+// or false if there is no such crossing.
+func (s *Segment) Intersect(vol Volume) (float64, bool) {
+	t, ok := vol.IntersectRay(s.Ray())
+	if !ok || t > 1 {
+		return 0, false
+	}
+	return t, true
+}
@@ -0,0 +1,77 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRay(t *testing.T) {
+	r := NewRay(NewVec3(1, 0, 0), NewVec3(0, 2, 0))
+	v := BlankVec3()
+	assert.Same(t, v, r.At(v, 1.5))
+	assertVec3Equal(t, 1, 3, 0, v, 1e-8)
+
+	assert.InDelta(t, 1.5, r.ClosestT(NewVec3(5, 3, 0)), 1e-8)
+	assertVec3Equal(t, 1, 3, 0, r.ClosestPoint(v, NewVec3(5, 3, 0)), 1e-8)
+	assert.InDelta(t, 4, r.Distance(NewVec3(5, 3, 0)), 1e-8)
+	// Points behind the origin are closest to the origin.
+	assert.Equal(t, 0.0, r.ClosestT(NewVec3(1, -3, 0)))
+	assert.InDelta(t, 3, r.Distance(NewVec3(1, -3, 0)), 1e-8)
+}
+
+func TestSegment(t *testing.T) {
+	s := NewSegment(NewVec3(0, 0, 0), NewVec3(3, 4, 0))
+	assert.InDelta(t, 5, s.Length(), 1e-8)
+	v := BlankVec3()
+	assert.Same(t, v, s.At(v, .5))
+	assertVec3Equal(t, 1.5, 2, 0, v, 1e-8)
+
+	assertVec3Equal(t, 3, 4, 0, s.ClosestPoint(v, NewVec3(10, 10, 0)), 1e-8)
+	assertVec3Equal(t, 0, 0, 0, s.ClosestPoint(v, NewVec3(-1, -1, 0)), 1e-8)
+	assert.InDelta(t, 2, s.Distance(NewVec3(1.5, 2, 2)), 1e-8)
+
+	r := s.Ray()
+	assertVec3Equal(t, 3, 4, 0, r.At(v, 1), 1e-8)
+
+	// Degenerate segment.
+	d := NewSegment(NewVec3(1, 1, 1), NewVec3(1, 1, 1))
+	assertVec3Equal(t, 1, 1, 1, d.ClosestPoint(v, NewVec3(2, 2, 2)), 1e-8)
+}
+
+func TestSegmentIntersect(t *testing.T) {
+	sp := NewSphere(NewVec3(0, 0, 0), 1)
+	s := NewSegment(NewVec3(-3, 0, 0), NewVec3(3, 0, 0))
+	tt, ok := s.Intersect(sp)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0/3, tt, 1e-8)
+
+	// Too short to reach the sphere.
+	s = NewSegment(NewVec3(-3, 0, 0), NewVec3(-2, 0, 0))
+	_, ok = s.Intersect(sp)
+	assert.False(t, ok)
+
+	pl := NewPlane(NewVec3(0, 0, 1), 1)
+	s = NewSegment(NewVec3(0, 0, 0), NewVec3(0, 0, 4))
+	tt, ok = s.Intersect(pl)
+	assert.True(t, ok)
+	assert.InDelta(t, .25, tt, 1e-8)
+}
+
+func TestVolumeClosestPointConsistency(t *testing.T) {
+	// The closest point lies on the boundary at |SignedDistance| away.
+	vols := []Volume{
+		NewSphere(NewVec3(1, 2, 3), 2),
+		NewPlane(NewVec3(0, 1, 0), -1),
+		NewAABB(NewVec3(-1, -2, -3), NewVec3(1, 2, 3)),
+	}
+	for _, vol := range vols {
+		for _, p := range []*Vec3{NewVec3(0, 0, 0), NewVec3(5, -4, 1), NewVec3(.5, .5, .5)} {
+			c := vol.ClosestPoint(BlankVec3(), p)
+			assert.InDelta(t, 0, vol.SignedDistance(c), 1e-8)
+			d := BlankVec3().Sub(p, c)
+			assert.InDelta(t, math.Abs(vol.SignedDistance(p)), d.Norm(), 1e-8)
+		}
+	}
+}
@@ -10,8 +10,9 @@ import (
 
 const max16f = float64(math.MaxUint16)
 
-// LoadHeatmap loads the heatmap from the given PNG file, uses its first row of pixels as color spectrum, and returns the gamma-corrected color spectrum.
-func LoadHeatmap(file string, gamma float64) ([]color.Color, error) {
+// LoadHeatmap loads the heatmap from the given PNG file, uses its first row of pixels as color spectrum, and returns the
+// Colormap of the gamma-corrected color spectrum, interpolating in SRGBSpace.
+func LoadHeatmap(file string, gamma float64) (*Colormap, error) {
 	// Load heatmap file.
 	f, err := os.Open(file)
 	if err != nil {
@@ -24,13 +25,13 @@ func LoadHeatmap(file string, gamma float64) ([]color.Color, error) {
 	}
 	rect := hm.Bounds()
 	width := rect.Max.X - rect.Min.X
-	heatmap := make([]color.Color, width)
+	heatmap := make([]color.NRGBA64, width)
 	for i := 0; i < width; i++ {
 		r, g, b, _ := hm.At(i+rect.Min.X, rect.Min.Y).RGBA()
 		r16 := uint16(math.Pow(float64(r)/max16f, gamma) * max16f)
 		g16 := uint16(math.Pow(float64(g)/max16f, gamma) * max16f)
 		b16 := uint16(math.Pow(float64(b)/max16f, gamma) * max16f)
-		heatmap[i] = color.RGBA64{R: r16, G: g16, B: b16, A: math.MaxUint16}
+		heatmap[i] = color.NRGBA64{R: r16, G: g16, B: b16, A: math.MaxUint16}
 	}
-	return heatmap, nil
+	return newColormap(heatmap, SRGBSpace, 1), nil
 }
@@ -10,22 +10,22 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func createTestImage(t *testing.T) (string, []color.Color) {
+func createTestImage(t *testing.T) (string, []color.NRGBA64) {
 	f, err := os.CreateTemp(os.TempDir(), "test-heatmap")
 	assert.NoError(t, err)
 	defer f.Close()
 
 	cnt := 200
-	colors := make([]color.Color, 0, cnt)
+	colors := make([]color.NRGBA64, 0, cnt)
 	c := 0
 	for i := 0; i < cnt; i++ {
 		switch c % 3 {
 		case 0:
-			colors = append(colors, color.RGBA64{R: 0xffff, G: 0, B: 0, A: 0xffff})
+			colors = append(colors, color.NRGBA64{R: 0xffff, G: 0, B: 0, A: 0xffff})
 		case 1:
-			colors = append(colors, color.RGBA64{R: 0, G: 0xffff, B: 0, A: 0xffff})
+			colors = append(colors, color.NRGBA64{R: 0, G: 0xffff, B: 0, A: 0xffff})
 		case 2:
-			colors = append(colors, color.RGBA64{R: 0, G: 0, B: 0xffff, A: 0xffff})
+			colors = append(colors, color.NRGBA64{R: 0, G: 0, B: 0xffff, A: 0xffff})
 		}
 		c++
 	}
@@ -56,5 +56,9 @@ func TestLoadHeatmap(t *testing.T) {
 
 	hm, err := LoadHeatmap(fn, 1.0)
 	assert.NoError(t, err)
-	assert.Equal(t, colors, hm)
+	assert.Equal(t, colors, hm.Stops())
+	assert.Equal(t, colors[0], hm.At(0))
+	assert.Equal(t, colors[199], hm.At(1))
+	assert.Equal(t, colors[100], hm.At(100./199))
+	assert.Equal(t, SRGBSpace, hm.Space())
 }
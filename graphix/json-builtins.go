@@ -0,0 +1,494 @@
+package graphix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON encoding of the built-in Transforms, Projectors, CameraOrbits and Easings.
+
+func init() {
+	RegisterTransformDecoder("identity", func(json.RawMessage) (Transform, error) { return IdentityTransform(), nil })
+	RegisterTransformDecoder("mat3", decodeArrayTransform[Mat3])
+	RegisterTransformDecoder("mat4", decodeArrayTransform[Mat4])
+	RegisterTransformDecoder("quaternion", decodeArrayTransform[Quaternion])
+	RegisterTransformDecoder("axis-angle-rotation", decodeAxisAngleRotation)
+	RegisterTransformDecoder("view", decodeViewTransform)
+
+	RegisterProjectorDecoder("orthographic", func(json.RawMessage) (Projector, error) { return NewOrthographic(), nil })
+	RegisterProjectorDecoder("perspective", decodePerspective)
+	RegisterProjectorDecoder("fov-perspective", decodeFOVPerspective)
+	RegisterProjectorDecoder("axonometric", decodeAxonometric)
+	RegisterProjectorDecoder("oblique", decodeOblique)
+	RegisterProjectorDecoder("fisheye", decodeFisheye)
+	RegisterProjectorDecoder("equirectangular", decodeEquirectangular)
+
+	RegisterCameraOrbitDecoder("circular", decodeCircularCameraOrbit)
+	RegisterCameraOrbitDecoder("stationary", decodeStationaryCameraOrbit)
+	RegisterCameraOrbitDecoder("keyframe", decodeKeyframeCameraOrbit)
+	RegisterCameraOrbitDecoder("concat", decodeConcatCameraOrbit)
+	RegisterCameraOrbitDecoder("reverse", decodeReverseCameraOrbit)
+	RegisterCameraOrbitDecoder("ping-pong", decodePingPongCameraOrbit)
+	RegisterCameraOrbitDecoder("retime", decodeRetimeCameraOrbit)
+
+	for e := linearEasing; e <= inOutSineEasing; e++ {
+		RegisterEasingDecoder(e.JSONType(), func(json.RawMessage) (Easing, error) { return e, nil })
+	}
+}
+
+// Transforms.
+
+func (identityTransform) JSONType() string         { return "identity" }
+func (identityTransform) JSONParams() (any, error) { return nil, nil }
+
+func (m *Mat3) JSONType() string         { return "mat3" }
+func (m *Mat3) JSONParams() (any, error) { return m, nil }
+
+func (m *Mat4) JSONType() string         { return "mat4" }
+func (m *Mat4) JSONParams() (any, error) { return m, nil }
+
+func (q *Quaternion) JSONType() string         { return "quaternion" }
+func (q *Quaternion) JSONParams() (any, error) { return q, nil }
+
+// Decodes transforms which are encoded as their own array.
+func decodeArrayTransform[T Mat3 | Mat4 | Quaternion, PT interface {
+	*T
+	Transform
+}](params json.RawMessage) (Transform, error) {
+	var t T
+	if err := json.Unmarshal(params, &t); err != nil {
+		return nil, err
+	}
+	return PT(&t), nil
+}
+
+func (aar *axisAngleRotation) JSONType() string         { return "axis-angle-rotation" }
+func (aar *axisAngleRotation) JSONParams() (any, error) { return &aar.mat, nil }
+
+func decodeAxisAngleRotation(params json.RawMessage) (Transform, error) {
+	aar := &axisAngleRotation{}
+	if err := json.Unmarshal(params, &aar.mat); err != nil {
+		return nil, err
+	}
+	return aar, nil
+}
+
+type viewTransformJSON struct {
+	Pos     *Vec3 `json:"pos"`
+	Forward *Vec3 `json:"forward"`
+	Up      *Vec3 `json:"up"`
+}
+
+func (vt *viewTransform) JSONType() string { return "view" }
+func (vt *viewTransform) JSONParams() (any, error) {
+	return &viewTransformJSON{Pos: vt.pos, Forward: BlankVec3().Scale(vt.uz, -1), Up: vt.uy}, nil
+}
+
+func decodeViewTransform(params json.RawMessage) (Transform, error) {
+	var vj viewTransformJSON
+	if err := json.Unmarshal(params, &vj); err != nil {
+		return nil, err
+	}
+	if vj.Pos == nil || vj.Forward == nil || vj.Up == nil {
+		return nil, errors.New("pos, forward and up must be present")
+	}
+	return NewViewTransform(vj.Pos, vj.Forward, vj.Up), nil
+}
+
+// Projectors.
+
+func (*orthographic) JSONType() string         { return "orthographic" }
+func (*orthographic) JSONParams() (any, error) { return nil, nil }
+
+type perspectiveJSON struct {
+	Dist float64 `json:"dist"`
+}
+
+func (per *perspective) JSONType() string         { return "perspective" }
+func (per *perspective) JSONParams() (any, error) { return &perspectiveJSON{Dist: per.d}, nil }
+
+func decodePerspective(params json.RawMessage) (Projector, error) {
+	var pj perspectiveJSON
+	if err := json.Unmarshal(params, &pj); err != nil {
+		return nil, err
+	}
+	return NewPerspective(pj.Dist), nil
+}
+
+type fovPerspectiveJSON struct {
+	TanX float64   `json:"tan_x"`
+	TanY float64   `json:"tan_y"`
+	Near jsonFloat `json:"near"`
+	Far  jsonFloat `json:"far"`
+}
+
+func (per *fovPerspective) JSONType() string { return "fov-perspective" }
+func (per *fovPerspective) JSONParams() (any, error) {
+	return &fovPerspectiveJSON{TanX: per.tanX, TanY: per.tanY, Near: jsonFloat(per.near), Far: jsonFloat(per.far)}, nil
+}
+
+func decodeFOVPerspective(params json.RawMessage) (Projector, error) {
+	var pj fovPerspectiveJSON
+	if err := json.Unmarshal(params, &pj); err != nil {
+		return nil, err
+	}
+	return &fovPerspective{tanX: pj.TanX, tanY: pj.TanY, near: float64(pj.Near), far: float64(pj.Far)}, nil
+}
+
+type axonometricJSON struct {
+	Rot *Mat3   `json:"rot"`
+	D0  float64 `json:"d0"`
+}
+
+func (ax *axonometric) JSONType() string { return "axonometric" }
+func (ax *axonometric) JSONParams() (any, error) {
+	return &axonometricJSON{Rot: ax.rot, D0: ax.d0}, nil
+}
+
+func decodeAxonometric(params json.RawMessage) (Projector, error) {
+	var aj axonometricJSON
+	if err := json.Unmarshal(params, &aj); err != nil {
+		return nil, err
+	}
+	if aj.Rot == nil {
+		return nil, errors.New("rot must be present")
+	}
+	return &axonometric{rot: aj.Rot, inv: (&Mat3{}).Transpose(aj.Rot), d0: aj.D0}, nil
+}
+
+type obliqueJSON struct {
+	DX float64 `json:"dx"`
+	DY float64 `json:"dy"`
+	D0 float64 `json:"d0"`
+}
+
+func (ob *oblique) JSONType() string { return "oblique" }
+func (ob *oblique) JSONParams() (any, error) {
+	return &obliqueJSON{DX: ob.dx, DY: ob.dy, D0: ob.d0}, nil
+}
+
+func decodeOblique(params json.RawMessage) (Projector, error) {
+	var oj obliqueJSON
+	if err := json.Unmarshal(params, &oj); err != nil {
+		return nil, err
+	}
+	return &oblique{dx: oj.DX, dy: oj.DY, d0: oj.D0}, nil
+}
+
+type fisheyeJSON struct {
+	Scale float64   `json:"scale"`
+	Near  jsonFloat `json:"near"`
+	Far   jsonFloat `json:"far"`
+}
+
+func (fe *fisheye) JSONType() string { return "fisheye" }
+func (fe *fisheye) JSONParams() (any, error) {
+	return &fisheyeJSON{Scale: fe.scale, Near: jsonFloat(fe.near), Far: jsonFloat(fe.far)}, nil
+}
+
+func decodeFisheye(params json.RawMessage) (Projector, error) {
+	var fj fisheyeJSON
+	if err := json.Unmarshal(params, &fj); err != nil {
+		return nil, err
+	}
+	return &fisheye{scale: fj.Scale, near: float64(fj.Near), far: float64(fj.Far)}, nil
+}
+
+type equirectangularJSON struct {
+	Near jsonFloat `json:"near"`
+	Far  jsonFloat `json:"far"`
+}
+
+func (eq *equirectangular) JSONType() string { return "equirectangular" }
+func (eq *equirectangular) JSONParams() (any, error) {
+	return &equirectangularJSON{Near: jsonFloat(eq.near), Far: jsonFloat(eq.far)}, nil
+}
+
+func decodeEquirectangular(params json.RawMessage) (Projector, error) {
+	var ej equirectangularJSON
+	if err := json.Unmarshal(params, &ej); err != nil {
+		return nil, err
+	}
+	return NewEquirectangular(float64(ej.Near), float64(ej.Far)), nil
+}
+
+// Camera orbits.
+
+type circularCameraOrbitJSON struct {
+	N           *Vec3           `json:"n"`
+	Pos         *Vec3           `json:"pos"`
+	Forward     *Vec3           `json:"forward"`
+	Up          *Vec3           `json:"up"`
+	Frames      int             `json:"frames"`
+	AngleOffset float64         `json:"angle_offset"`
+	Projector   json.RawMessage `json:"projector"`
+	Screen      *Screen         `json:"screen"`
+}
+
+func (cir *circularCameraOrbit) JSONType() string { return "circular" }
+func (cir *circularCameraOrbit) JSONParams() (any, error) {
+	pr, err := EncodeProjector(cir.pr)
+	if err != nil {
+		return nil, err
+	}
+	return &circularCameraOrbitJSON{
+		N:           cir.n,
+		Pos:         cir.pos,
+		Forward:     cir.forward,
+		Up:          cir.up,
+		Frames:      cir.frames,
+		AngleOffset: cir.angleOffset,
+		Projector:   pr,
+		Screen:      cir.sc,
+	}, nil
+}
+
+func decodeCircularCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var cj circularCameraOrbitJSON
+	if err := json.Unmarshal(params, &cj); err != nil {
+		return nil, err
+	}
+	if cj.N == nil || cj.Pos == nil || cj.Forward == nil || cj.Up == nil || cj.Screen == nil {
+		return nil, errors.New("n, pos, forward, up and screen must be present")
+	}
+	if cj.Frames <= 0 {
+		return nil, errors.New("frames must be positive")
+	}
+	pr, err := DecodeProjector(cj.Projector)
+	if err != nil {
+		return nil, err
+	}
+	return NewCircularCameraOrbit(cj.N, cj.Pos, cj.Forward, cj.Up, cj.Frames, cj.AngleOffset, pr, cj.Screen), nil
+}
+
+type stationaryCameraOrbitJSON struct {
+	Camera json.RawMessage `json:"camera"`
+	Frames int             `json:"frames"`
+}
+
+func (st *stationaryCameraOrbit) JSONType() string { return "stationary" }
+func (st *stationaryCameraOrbit) JSONParams() (any, error) {
+	cam, err := EncodeCamera(st.cam)
+	if err != nil {
+		return nil, err
+	}
+	return &stationaryCameraOrbitJSON{Camera: cam, Frames: st.frames}, nil
+}
+
+func decodeStationaryCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var sj stationaryCameraOrbitJSON
+	if err := json.Unmarshal(params, &sj); err != nil {
+		return nil, err
+	}
+	if sj.Frames <= 0 {
+		return nil, errors.New("frames must be positive")
+	}
+	cam, err := DecodeCamera(sj.Camera)
+	if err != nil {
+		return nil, err
+	}
+	return NewStationaryCamera(cam, sj.Frames), nil
+}
+
+type cameraKeyframeJSON struct {
+	Frame       int             `json:"frame"`
+	Pos         *Vec3           `json:"pos"`
+	Orientation *Quaternion     `json:"orientation"`
+	Velocity    *Vec3           `json:"velocity"`
+	Easing      json.RawMessage `json:"easing"`
+}
+
+type keyframeCameraOrbitJSON struct {
+	Keyframes []*cameraKeyframeJSON `json:"keyframes"`
+	Projector json.RawMessage       `json:"projector"`
+	Screen    *Screen               `json:"screen"`
+}
+
+func (ko *keyframeCameraOrbit) JSONType() string { return "keyframe" }
+func (ko *keyframeCameraOrbit) JSONParams() (any, error) {
+	pr, err := EncodeProjector(ko.pr)
+	if err != nil {
+		return nil, err
+	}
+	kj := &keyframeCameraOrbitJSON{Projector: pr, Screen: ko.sc}
+	for i, k := range ko.keys {
+		e, err := EncodeEasing(k.Easing)
+		if err != nil {
+			return nil, err
+		}
+		// The resolved tangents are recorded as velocities, so that decoding with HermiteSpline reproduces the orbit
+		// regardless of the spline it was created with.
+		kj.Keyframes = append(kj.Keyframes, &cameraKeyframeJSON{
+			Frame:       k.Frame,
+			Pos:         k.Pos,
+			Orientation: k.Orientation,
+			Velocity:    ko.tangents[i],
+			Easing:      e,
+		})
+	}
+	return kj, nil
+}
+
+func decodeKeyframeCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var kj keyframeCameraOrbitJSON
+	if err := json.Unmarshal(params, &kj); err != nil {
+		return nil, err
+	}
+	pr, err := DecodeProjector(kj.Projector)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*CameraKeyframe, len(kj.Keyframes))
+	for i, k := range kj.Keyframes {
+		keys[i] = &CameraKeyframe{Frame: k.Frame, Pos: k.Pos, Orientation: k.Orientation, Velocity: k.Velocity}
+		if k.Easing != nil {
+			if keys[i].Easing, err = DecodeEasing(k.Easing); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return NewKeyframeCameraOrbit(keys, HermiteSpline, pr, kj.Screen)
+}
+
+type concatCameraOrbitJSON struct {
+	Orbits []json.RawMessage `json:"orbits"`
+}
+
+func (co *concatCameraOrbit) JSONType() string { return "concat" }
+func (co *concatCameraOrbit) JSONParams() (any, error) {
+	cj := &concatCameraOrbitJSON{}
+	for _, o := range co.orbits {
+		oj, err := EncodeCameraOrbit(o)
+		if err != nil {
+			return nil, err
+		}
+		cj.Orbits = append(cj.Orbits, oj)
+	}
+	return cj, nil
+}
+
+func decodeConcatCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var cj concatCameraOrbitJSON
+	if err := json.Unmarshal(params, &cj); err != nil {
+		return nil, err
+	}
+	orbits := make([]CameraOrbit, len(cj.Orbits))
+	frames := 0
+	for i, oj := range cj.Orbits {
+		o, err := DecodeCameraOrbit(oj)
+		if err != nil {
+			return nil, err
+		}
+		orbits[i] = o
+		frames += o.Frames()
+	}
+	if frames <= 0 {
+		return nil, errors.New("concatenated orbits must have positive frames")
+	}
+	return ConcatCameraOrbits(orbits...), nil
+}
+
+// Parameters of combinators wrapping a single orbit.
+type wrappedCameraOrbitJSON struct {
+	Orbit json.RawMessage `json:"orbit"`
+}
+
+func (ro *reverseCameraOrbit) JSONType() string         { return "reverse" }
+func (ro *reverseCameraOrbit) JSONParams() (any, error) { return encodeWrappedCameraOrbit(ro.o) }
+
+func (po *pingPongCameraOrbit) JSONType() string         { return "ping-pong" }
+func (po *pingPongCameraOrbit) JSONParams() (any, error) { return encodeWrappedCameraOrbit(po.o) }
+
+func encodeWrappedCameraOrbit(o CameraOrbit) (*wrappedCameraOrbitJSON, error) {
+	oj, err := EncodeCameraOrbit(o)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedCameraOrbitJSON{Orbit: oj}, nil
+}
+
+func decodeWrappedCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var wj wrappedCameraOrbitJSON
+	if err := json.Unmarshal(params, &wj); err != nil {
+		return nil, err
+	}
+	return DecodeCameraOrbit(wj.Orbit)
+}
+
+func decodeReverseCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	o, err := decodeWrappedCameraOrbit(params)
+	if err != nil {
+		return nil, err
+	}
+	return ReverseCameraOrbit(o), nil
+}
+
+func decodePingPongCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	o, err := decodeWrappedCameraOrbit(params)
+	if err != nil {
+		return nil, err
+	}
+	return PingPongCameraOrbit(o), nil
+}
+
+type retimeCameraOrbitJSON struct {
+	Orbit  json.RawMessage `json:"orbit"`
+	Easing json.RawMessage `json:"easing"`
+	Frames int             `json:"frames"`
+}
+
+func (rt *retimeCameraOrbit) JSONType() string { return "retime" }
+func (rt *retimeCameraOrbit) JSONParams() (any, error) {
+	oj, err := EncodeCameraOrbit(rt.o)
+	if err != nil {
+		return nil, err
+	}
+	ej, err := EncodeEasing(rt.e)
+	if err != nil {
+		return nil, err
+	}
+	return &retimeCameraOrbitJSON{Orbit: oj, Easing: ej, Frames: rt.frames}, nil
+}
+
+func decodeRetimeCameraOrbit(params json.RawMessage) (CameraOrbit, error) {
+	var rj retimeCameraOrbitJSON
+	if err := json.Unmarshal(params, &rj); err != nil {
+		return nil, err
+	}
+	if rj.Frames <= 0 {
+		return nil, errors.New("frames must be positive")
+	}
+	o, err := DecodeCameraOrbit(rj.Orbit)
+	if err != nil {
+		return nil, err
+	}
+	e, err := DecodeEasing(rj.Easing)
+	if err != nil {
+		return nil, err
+	}
+	return RetimeCameraOrbit(o, e, rj.Frames), nil
+}
+
+// Easings.
+
+func (e easing) JSONType() string {
+	switch e {
+	case linearEasing:
+		return "linear"
+	case inQuadEasing:
+		return "in-quad"
+	case outQuadEasing:
+		return "out-quad"
+	case inOutQuadEasing:
+		return "in-out-quad"
+	case inOutCubicEasing:
+		return "in-out-cubic"
+	case inOutSineEasing:
+		return "in-out-sine"
+	default:
+		panic(fmt.Sprintf("unknown easing %d", int(e)))
+	}
+}
+
+func (easing) JSONParams() (any, error) { return nil, nil }
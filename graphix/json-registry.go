@@ -0,0 +1,272 @@
+package graphix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// JSONSchemaVersion is the version of the JSON documents written by MarshalCamera and MarshalCameraOrbit.
+const JSONSchemaVersion = 1
+
+// JSONEncodable is implemented by Transforms, Projectors, CameraOrbits and Easings which can be encoded into JSON.
+// A value is encoded as {"type": JSONType(), "params": JSONParams()}, and decoded by the decoder registered with its
+// type name, see RegisterTransformDecoder, RegisterProjectorDecoder, RegisterCameraOrbitDecoder and
+// RegisterEasingDecoder.
+type JSONEncodable interface {
+	// JSONType returns the name the value's decoder is registered with.
+	JSONType() string
+	// JSONParams returns the parameters of the value to be encoded with encoding/json, or nil if it has none.
+	JSONParams() (any, error)
+}
+
+// Envelope of a JSONEncodable value.
+type typedJSON struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Decoders of JSONEncodable values of type T keyed by their type names.
+type decoderRegistry[T any] struct {
+	kind     string
+	mu       sync.RWMutex
+	decoders map[string]func(params json.RawMessage) (T, error)
+}
+
+func newDecoderRegistry[T any](kind string) *decoderRegistry[T] {
+	return &decoderRegistry[T]{kind: kind, decoders: make(map[string]func(json.RawMessage) (T, error))}
+}
+
+var (
+	transformDecoders   = newDecoderRegistry[Transform]("transform")
+	projectorDecoders   = newDecoderRegistry[Projector]("projector")
+	cameraOrbitDecoders = newDecoderRegistry[CameraOrbit]("camera orbit")
+	easingDecoders      = newDecoderRegistry[Easing]("easing")
+)
+
+// RegisterTransformDecoder registers the decoder of the Transforms whose JSONType is name.
+// It panics if name is already registered.
+func RegisterTransformDecoder(name string, dec func(params json.RawMessage) (Transform, error)) {
+	transformDecoders.register(name, dec)
+}
+
+// RegisterProjectorDecoder registers the decoder of the Projectors whose JSONType is name.
+// It panics if name is already registered.
+func RegisterProjectorDecoder(name string, dec func(params json.RawMessage) (Projector, error)) {
+	projectorDecoders.register(name, dec)
+}
+
+// RegisterCameraOrbitDecoder registers the decoder of the CameraOrbits whose JSONType is name.
+// It panics if name is already registered.
+func RegisterCameraOrbitDecoder(name string, dec func(params json.RawMessage) (CameraOrbit, error)) {
+	cameraOrbitDecoders.register(name, dec)
+}
+
+// RegisterEasingDecoder registers the decoder of the Easings whose JSONType is name.
+// It panics if name is already registered.
+func RegisterEasingDecoder(name string, dec func(params json.RawMessage) (Easing, error)) {
+	easingDecoders.register(name, dec)
+}
+
+func (r *decoderRegistry[T]) register(name string, dec func(json.RawMessage) (T, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.decoders[name]; ok {
+		panic(fmt.Sprintf("%v decoder %q is already registered", r.kind, name))
+	}
+	r.decoders[name] = dec
+}
+
+func (r *decoderRegistry[T]) encode(v any) (json.RawMessage, error) {
+	je, ok := v.(JSONEncodable)
+	if !ok {
+		return nil, fmt.Errorf("%v of type %T is not JSON encodable", r.kind, v)
+	}
+	params, err := je.JSONParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %v %q: %v", r.kind, je.JSONType(), err)
+	}
+	tj := &typedJSON{Type: je.JSONType()}
+	if params != nil {
+		if tj.Params, err = json.Marshal(params); err != nil {
+			return nil, fmt.Errorf("failed to encode %v %q: %v", r.kind, je.JSONType(), err)
+		}
+	}
+	return json.Marshal(tj)
+}
+
+func (r *decoderRegistry[T]) decode(data json.RawMessage) (T, error) {
+	var zero T
+	var tj typedJSON
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return zero, fmt.Errorf("failed to decode %v: %v", r.kind, err)
+	}
+	r.mu.RLock()
+	dec, ok := r.decoders[tj.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("unknown %v type %q", r.kind, tj.Type)
+	}
+	v, err := dec(tj.Params)
+	if err != nil {
+		return zero, fmt.Errorf("failed to decode %v %q: %v", r.kind, tj.Type, err)
+	}
+	return v, nil
+}
+
+func EncodeTransform(t Transform) (json.RawMessage, error)     { return transformDecoders.encode(t) }
+func DecodeTransform(data json.RawMessage) (Transform, error)  { return transformDecoders.decode(data) }
+func EncodeProjector(pr Projector) (json.RawMessage, error)    { return projectorDecoders.encode(pr) }
+func DecodeProjector(data json.RawMessage) (Projector, error)  { return projectorDecoders.decode(data) }
+func EncodeCameraOrbit(o CameraOrbit) (json.RawMessage, error) { return cameraOrbitDecoders.encode(o) }
+func DecodeCameraOrbit(data json.RawMessage) (CameraOrbit, error) {
+	return cameraOrbitDecoders.decode(data)
+}
+func EncodeEasing(e Easing) (json.RawMessage, error)    { return easingDecoders.encode(e) }
+func DecodeEasing(data json.RawMessage) (Easing, error) { return easingDecoders.decode(data) }
+
+type cameraJSON struct {
+	View      json.RawMessage `json:"view"`
+	Projector json.RawMessage `json:"projector"`
+	Screen    *Screen         `json:"screen"`
+}
+
+// EncodeCamera encodes cam into JSON, for use in the parameters of JSONEncodable values.
+// An error is returned if the view transform or the projector of cam is not JSONEncodable.
+func EncodeCamera(cam *Camera) (json.RawMessage, error) {
+	cj, err := newCameraJSON(cam)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cj)
+}
+
+// DecodeCamera is the inverse of EncodeCamera.
+func DecodeCamera(data json.RawMessage) (*Camera, error) {
+	var cj cameraJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return nil, fmt.Errorf("failed to decode camera: %v", err)
+	}
+	return cj.camera()
+}
+
+func newCameraJSON(cam *Camera) (*cameraJSON, error) {
+	if cam == nil {
+		return nil, errors.New("camera must not be nil")
+	}
+	vt, err := EncodeTransform(cam.vt)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := EncodeProjector(cam.pr)
+	if err != nil {
+		return nil, err
+	}
+	return &cameraJSON{View: vt, Projector: pr, Screen: cam.sc}, nil
+}
+
+func (cj *cameraJSON) camera() (*Camera, error) {
+	vt, err := DecodeTransform(cj.View)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := DecodeProjector(cj.Projector)
+	if err != nil {
+		return nil, err
+	}
+	if cj.Screen == nil {
+		return nil, errors.New("screen must be present")
+	}
+	return NewCamera(vt, pr, cj.Screen), nil
+}
+
+type cameraDocument struct {
+	Version int `json:"version"`
+	cameraJSON
+}
+
+type cameraOrbitDocument struct {
+	Version int             `json:"version"`
+	Orbit   json.RawMessage `json:"orbit"`
+}
+
+// MarshalCamera returns the versioned JSON document describing cam.
+func MarshalCamera(cam *Camera) ([]byte, error) {
+	cj, err := newCameraJSON(cam)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(&cameraDocument{Version: JSONSchemaVersion, cameraJSON: *cj}, "", "  ")
+}
+
+// UnmarshalCamera returns the camera described by the JSON document produced by MarshalCamera.
+func UnmarshalCamera(data []byte) (*Camera, error) {
+	var doc cameraDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode camera: %v", err)
+	}
+	if err := checkSchemaVersion(doc.Version); err != nil {
+		return nil, err
+	}
+	return doc.camera()
+}
+
+// MarshalCameraOrbit returns the versioned JSON document describing o.
+func MarshalCameraOrbit(o CameraOrbit) ([]byte, error) {
+	orbit, err := EncodeCameraOrbit(o)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(&cameraOrbitDocument{Version: JSONSchemaVersion, Orbit: orbit}, "", "  ")
+}
+
+// UnmarshalCameraOrbit returns the camera orbit described by the JSON document produced by MarshalCameraOrbit.
+func UnmarshalCameraOrbit(data []byte) (CameraOrbit, error) {
+	var doc cameraOrbitDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode camera orbit: %v", err)
+	}
+	if err := checkSchemaVersion(doc.Version); err != nil {
+		return nil, err
+	}
+	return DecodeCameraOrbit(doc.Orbit)
+}
+
+func checkSchemaVersion(v int) error {
+	if v != JSONSchemaVersion {
+		return fmt.Errorf("unsupported schema version %v, expecting %v", v, JSONSchemaVersion)
+	}
+	return nil
+}
+
+// jsonFloat encodes infinities, which are valid clipping planes but not valid JSON numbers, as strings.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	switch {
+	case math.IsInf(float64(f), 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(float64(f), -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return json.Marshal(float64(f))
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"+Inf"`:
+		*f = jsonFloat(math.Inf(1))
+		return nil
+	case `"-Inf"`:
+		*f = jsonFloat(math.Inf(-1))
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
@@ -0,0 +1,187 @@
+package graphix
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertCameraEqual verifies that cam maps sample points the same way as exp.
+func assertCameraEqual(t *testing.T, exp, cam *Camera) {
+	for _, v := range []*Vec3{NewVec3(.3, -.2, -2), NewVec3(1, 2, -5), NewVec3(-3, .5, -1.5)} {
+		ev := exp.ViewTransform().Apply(BlankVec3(), v)
+		assert.Equal(t, ev, cam.ViewTransform().Apply(BlankVec3(), v))
+		ep := exp.Projector().Project(BlankProjection(), ev)
+		assert.Equal(t, ep, cam.Projector().Project(BlankProjection(), ev))
+		assert.Equal(t, exp.Screen().Map(BlankProjection(), ep), cam.Screen().Map(BlankProjection(), ep))
+	}
+	assert.Equal(t, exp.Projector().NearZClip(), cam.Projector().NearZClip())
+	ef, eok := exp.Projector().(FarClipper)
+	cf, cok := cam.Projector().(FarClipper)
+	if assert.Equal(t, eok, cok) && eok {
+		assert.Equal(t, ef.FarZClip(), cf.FarZClip())
+	}
+	assert.Equal(t, exp.Screen().Width(), cam.Screen().Width())
+	assert.Equal(t, exp.Screen().Height(), cam.Screen().Height())
+}
+
+func assertCameraOrbitEqual(t *testing.T, exp, o CameraOrbit) {
+	assert.Equal(t, exp.Frames(), o.Frames())
+	for i := range exp.Frames() {
+		assertCameraEqual(t, exp.GetCamera(i), o.GetCamera(i))
+	}
+}
+
+func TestCameraJSON(t *testing.T) {
+	vt, err := LookAt(NewVec3(1, 2, 3), NewVec3(0, 0, -1), NewVec3(0, 1, 0))
+	assert.NoError(t, err)
+	vts := []Transform{
+		vt,
+		IdentityTransform(),
+		NewAxisAngleRotation(NewVec3(0, 1, 0), .4),
+		NewAxisAngleRotation(NewVec3(0, 1, 0), .4).Mat4().Linear(),
+		Compose(vt, NewScale(1, 2, 3)),
+		NewAxisAngleQuaternion(NewVec3(1, 0, 0), -.3),
+		NewStereoRig(NewCamera(vt, nil, nil), .1, 10).Left().ViewTransform(),
+	}
+	prs := []Projector{
+		NewOrthographic(),
+		NewPerspective(2),
+		NewFOVPerspective(1, 1.5, .1, math.Inf(1)),
+		NewFOVPerspective(1, 1.5, .1, 100),
+		NewIsometric(5),
+		NewCabinet(math.Pi/6, 1),
+		NewFisheye(math.Pi, .1, 1000),
+		NewEquirectangular(.1, math.Inf(1)),
+	}
+	sc := NewScreen(640, 480, -2, -1.5, 2, 1.5)
+	for _, vt := range vts {
+		for _, pr := range prs {
+			cam := NewCamera(vt, pr, sc)
+			data, err := MarshalCamera(cam)
+			assert.NoError(t, err)
+			decoded, err := UnmarshalCamera(data)
+			assert.NoError(t, err)
+			assertCameraEqual(t, cam, decoded)
+		}
+	}
+}
+
+func TestCameraJSONSchema(t *testing.T) {
+	cam := NewCamera(IdentityTransform(), NewFOVPerspective(1, 1, .1, math.Inf(1)), NewFOVScreen(10, 20))
+	data, err := MarshalCamera(cam)
+	assert.NoError(t, err)
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, float64(JSONSchemaVersion), doc["version"])
+	assert.Equal(t, map[string]any{"type": "identity"}, doc["view"])
+	assert.Equal(t, "fov-perspective", doc["projector"].(map[string]any)["type"])
+	assert.Equal(t, "+Inf", doc["projector"].(map[string]any)["params"].(map[string]any)["far"])
+	assert.Equal(
+		t,
+		map[string]any{"width": float64(10), "height": float64(20), "x0": float64(-1), "y0": float64(-1), "x1": float64(1), "y1": float64(1)},
+		doc["screen"],
+	)
+
+	_, err = UnmarshalCamera([]byte(`{"version":2}`))
+	assert.ErrorContains(t, err, "unsupported schema version")
+	_, err = UnmarshalCamera([]byte(`{"version":1,"view":{"type":"unknown"}}`))
+	assert.ErrorContains(t, err, `unknown transform type "unknown"`)
+	_, err = UnmarshalCamera([]byte(`{"version":1`))
+	assert.Error(t, err)
+	_, err = UnmarshalCamera([]byte(`{"version":1,"view":{"type":"identity"},"projector":{"type":"orthographic"}}`))
+	assert.ErrorContains(t, err, "screen must be present")
+	_, err = UnmarshalCamera([]byte(
+		`{"version":1,"view":{"type":"identity"},"projector":{"type":"orthographic"},"screen":{"width":0,"height":10}}`,
+	))
+	assert.ErrorContains(t, err, "screen dimension must be positive, got 0x10")
+	_, err = UnmarshalCamera([]byte(
+		`{"version":1,"view":{"type":"identity"},"projector":{"type":"orthographic"},"screen":{"width":10,"height":10}}`,
+	))
+	assert.ErrorContains(t, err, "screen rectangle must not be empty")
+
+	// Transforms without a JSON encoding.
+	_, err = MarshalCamera(NewCamera(TransformFunc(IdentityTransform().Apply), NewOrthographic(), nil))
+	assert.ErrorContains(t, err, "not JSON encodable")
+	_, err = MarshalCamera(nil)
+	assert.Error(t, err)
+}
+
+// A custom projector taking part in JSON encoding.
+type scaledOrthographic struct {
+	Scale float64 `json:"scale"`
+}
+
+func (so *scaledOrthographic) NearZClip() float64 { return 0 }
+func (so *scaledOrthographic) Project(p *Projection, v *Vec3) *Projection {
+	p[0], p[1], p[2] = v[0]*so.Scale, v[1]*so.Scale, -v[2]
+	return p
+}
+func (so *scaledOrthographic) Unproject(v *Vec3, p *Projection) *Vec3 {
+	v[0], v[1], v[2] = p[0]/so.Scale, p[1]/so.Scale, -p[2]
+	return v
+}
+func (so *scaledOrthographic) JSONType() string         { return "test-scaled-orthographic" }
+func (so *scaledOrthographic) JSONParams() (any, error) { return so, nil }
+
+func TestRegisterProjectorDecoder(t *testing.T) {
+	RegisterProjectorDecoder("test-scaled-orthographic", func(params json.RawMessage) (Projector, error) {
+		so := &scaledOrthographic{}
+		return so, json.Unmarshal(params, so)
+	})
+	assert.Panics(t, func() {
+		RegisterProjectorDecoder("orthographic", func(json.RawMessage) (Projector, error) { return nil, nil })
+	})
+	cam := NewCamera(IdentityTransform(), &scaledOrthographic{Scale: 3}, NewFOVScreen(10, 10))
+	data, err := MarshalCamera(cam)
+	assert.NoError(t, err)
+	decoded, err := UnmarshalCamera(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &scaledOrthographic{Scale: 3}, decoded.Projector())
+}
+
+func TestCameraOrbitJSON(t *testing.T) {
+	pr, sc := NewPerspective(2), NewFOVScreen(32, 24)
+	cir := NewCircularCameraOrbit(
+		NewVec3(0, 1, 0), NewVec3(0, 0, 5), NewVec3(0, 0, -1), NewVec3(0, 1, 0), 8, .3, pr, sc,
+	)
+	fz, fx, uy := NewVec3(0, 0, -1), NewVec3(1, 0, 0), NewVec3(0, 1, 0)
+	ko, err := NewKeyframeCameraOrbit([]*CameraKeyframe{
+		{Frame: 1, Pos: NewVec3(0, 0, 5), Orientation: NewCameraOrientation(fz, uy), Easing: EaseInOutSine()},
+		{Frame: 4, Pos: NewVec3(1, 2, 3), Orientation: NewCameraOrientation(fx, uy)},
+		{Frame: 9, Pos: NewVec3(-3, 0, 1), Orientation: NewCameraOrientation(fz, uy), Easing: EaseOutQuad()},
+	}, CatmullRomSpline, NewIsometric(5), sc)
+	assert.NoError(t, err)
+	st := NewStationaryCamera(NewCamera(IdentityTransform(), NewOrthographic(), sc), 3)
+	orbits := []CameraOrbit{
+		cir,
+		ko,
+		st,
+		ConcatCameraOrbits(st, cir, HoldCameraOrbit(ko, 5, 2)),
+		ReverseCameraOrbit(cir),
+		PingPongCameraOrbit(ko),
+		RetimeCameraOrbit(cir, EaseInOutCubic(), 13),
+	}
+	for _, o := range orbits {
+		data, err := MarshalCameraOrbit(o)
+		assert.NoError(t, err)
+		decoded, err := UnmarshalCameraOrbit(data)
+		assert.NoError(t, err)
+		assertCameraOrbitEqual(t, o, decoded)
+	}
+
+	_, err = MarshalCameraOrbit(RetimeCameraOrbit(cir, EasingFunc(func(t float64) float64 { return t }), 3))
+	assert.ErrorContains(t, err, "not JSON encodable")
+	_, err = UnmarshalCameraOrbit([]byte(`{"version":0,"orbit":{}}`))
+	assert.ErrorContains(t, err, "unsupported schema version")
+	_, err = UnmarshalCameraOrbit([]byte(`{"version":1,"orbit":{"type":"concat","params":{"orbits":[]}}}`))
+	assert.Error(t, err)
+	_, err = UnmarshalCameraOrbit([]byte(`{"version":1,"orbit":{"type":"circular","params":{"frames":1}}}`))
+	assert.ErrorContains(t, err, "n, pos, forward, up and screen must be present")
+	data, err := MarshalCameraOrbit(NewStationaryCamera(NewCamera(IdentityTransform(), NewOrthographic(), sc), 0))
+	assert.NoError(t, err)
+	_, err = UnmarshalCameraOrbit(data)
+	assert.ErrorContains(t, err, "frames must be positive")
+}
@@ -0,0 +1,161 @@
+package graphix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SplineKind selects how a keyframe camera orbit interpolates positions between keyframes.
+type SplineKind int
+
+const (
+	// CatmullRomSpline passes through every keyframe position, with tangents estimated from the neighboring keyframes.
+	CatmullRomSpline SplineKind = iota
+	// HermiteSpline uses the keyframes' Velocity as tangents, falling back to the Catmull-Rom estimate where it is nil.
+	HermiteSpline
+)
+
+// CameraKeyframe is the camera pose at a given frame of a keyframe camera orbit.
+type CameraKeyframe struct {
+	Frame int
+	Pos   *Vec3
+	// Orientation is the unit quaternion rotating the canonical camera (looking at -z with +y as up) into the
+	// camera's orientation in world coordinates, see NewCameraOrientation.
+	Orientation *Quaternion
+	// Velocity is the velocity of the camera position at this keyframe in world units per frame.
+	// It is only used by HermiteSpline.
+	Velocity *Vec3
+	// Easing remaps the time of the segment from this keyframe to the next one, nil means LinearEasing.
+	Easing Easing
+}
+
+// NewCameraOrientation returns the orientation of a camera facing forward with up as its up direction,
+// suitable for CameraKeyframe.Orientation.
+// It is the caller's responsibility to ensure that forward and up are mutually orthogonal and normalized.
+func NewCameraOrientation(forward, up *Vec3) *Quaternion {
+	// The columns of the rotation are the images of the canonical camera's right (+x), up (+y) and back (+z) directions.
+	right := BlankVec3().Cross(forward, up)
+	return NewMat3Quaternion(&Mat3{
+		{right[0], up[0], -forward[0]},
+		{right[1], up[1], -forward[1]},
+		{right[2], up[2], -forward[2]},
+	})
+}
+
+type keyframeCameraOrbit struct {
+	keys []*CameraKeyframe
+	// Position tangents in world units per frame, one for each keyframe.
+	tangents []*Vec3
+	frames   int
+
+	pr Projector
+	sc *Screen
+}
+
+var _ CameraOrbit = (*keyframeCameraOrbit)(nil)
+
+// NewKeyframeCameraOrbit creates a camera orbit interpolating keys, where position is interpolated with spline and
+// orientation with Slerp. The orbit has keys[len(keys)-1].Frame+1 frames, and frames before the first keyframe hold
+// its pose. An error is returned if keys is empty, has nil or non-finite poses, or if the keyframes' frames are not
+// non-negative and strictly increasing.
+func NewKeyframeCameraOrbit(keys []*CameraKeyframe, spline SplineKind, pr Projector, sc *Screen) (CameraOrbit, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyframes must not be empty")
+	}
+	if pr == nil || sc == nil {
+		return nil, errors.New("projector and screen must not be nil")
+	}
+	if spline != CatmullRomSpline && spline != HermiteSpline {
+		return nil, fmt.Errorf("unknown spline kind %v", spline)
+	}
+	ko := &keyframeCameraOrbit{
+		keys:     make([]*CameraKeyframe, len(keys)),
+		tangents: make([]*Vec3, len(keys)),
+		frames:   keys[len(keys)-1].Frame + 1,
+		pr:       pr,
+		sc:       sc,
+	}
+	for i, k := range keys {
+		if k.Pos == nil || k.Orientation == nil {
+			return nil, fmt.Errorf("keyframe %v must have a position and an orientation", i)
+		}
+		if err := checkFinite(fmt.Sprintf("keyframe %v position", i), k.Pos); err != nil {
+			return nil, err
+		}
+		if n := k.Orientation.Norm(); math.Abs(n-1) > orthonormalTolerance {
+			return nil, fmt.Errorf("keyframe %v orientation must be normalized, got norm %v", i, n)
+		}
+		if k.Frame < 0 || (i > 0 && k.Frame <= keys[i-1].Frame) {
+			return nil, errors.New("keyframe frames must be non-negative and strictly increasing")
+		}
+		ko.keys[i] = &CameraKeyframe{
+			Frame:       k.Frame,
+			Pos:         NewCopyVec3(k.Pos),
+			Orientation: NewCopyQuaternion(k.Orientation),
+			Easing:      k.Easing,
+		}
+		if ko.keys[i].Easing == nil {
+			ko.keys[i].Easing = LinearEasing()
+		}
+		if spline == HermiteSpline && k.Velocity != nil {
+			if err := checkFinite(fmt.Sprintf("keyframe %v velocity", i), k.Velocity); err != nil {
+				return nil, err
+			}
+			ko.tangents[i] = NewCopyVec3(k.Velocity)
+		}
+	}
+	for i := range ko.keys {
+		if ko.tangents[i] == nil {
+			ko.tangents[i] = ko.estimateTangent(i)
+		}
+	}
+	return ko, nil
+}
+
+// estimateTangent returns the finite difference of the neighboring keyframe positions around keyframe i,
+// which is one-sided at both ends.
+func (ko *keyframeCameraOrbit) estimateTangent(i int) *Vec3 {
+	lo, hi := max(i-1, 0), min(i+1, len(ko.keys)-1)
+	if lo == hi {
+		return BlankVec3()
+	}
+	t := BlankVec3().Sub(ko.keys[hi].Pos, ko.keys[lo].Pos)
+	return t.Scale(t, 1/float64(ko.keys[hi].Frame-ko.keys[lo].Frame))
+}
+
+func (ko *keyframeCameraOrbit) Frames() int { return ko.frames }
+
+func (ko *keyframeCameraOrbit) GetCamera(i int) *Camera {
+	i = i % ko.frames
+	// Index of the first keyframe after frame i.
+	n := sort.Search(len(ko.keys), func(j int) bool { return ko.keys[j].Frame > i })
+	if n == 0 {
+		return ko.poseCamera(ko.keys[0].Pos, ko.keys[0].Orientation)
+	}
+	if n == len(ko.keys) {
+		last := ko.keys[n-1]
+		return ko.poseCamera(last.Pos, last.Orientation)
+	}
+	k0, k1 := ko.keys[n-1], ko.keys[n]
+	df := float64(k1.Frame - k0.Frame)
+	s := k0.Easing.Ease(float64(i-k0.Frame) / df)
+
+	// Cubic Hermite basis, with tangents scaled from per-frame to per-segment.
+	s2, s3 := s*s, s*s*s
+	h00, h10, h01, h11 := 2*s3-3*s2+1, s3-2*s2+s, 3*s2-2*s3, s3-s2
+	m0, m1 := ko.tangents[n-1], ko.tangents[n]
+	pos := BlankVec3()
+	for j := range 3 {
+		pos[j] = h00*k0.Pos[j] + h10*df*m0[j] + h01*k1.Pos[j] + h11*df*m1[j]
+	}
+	q := IdentityQuaternion().Slerp(k0.Orientation, k1.Orientation, s)
+	return ko.poseCamera(pos, q)
+}
+
+func (ko *keyframeCameraOrbit) poseCamera(pos *Vec3, q *Quaternion) *Camera {
+	forward := q.Apply(BlankVec3(), NewVec3(0, 0, -1))
+	up := q.Apply(BlankVec3(), NewVec3(0, 1, 0))
+	return NewCamera(NewViewTransform(pos, forward, up), ko.pr, ko.sc)
+}
@@ -0,0 +1,131 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertCameraPose verifies the view transform of cam maps pos to the origin, and forward/up to -z/+y.
+func assertCameraPose(t *testing.T, pos, forward, up *Vec3, cam *Camera, delta float64) {
+	vt := cam.ViewTransform()
+	assertVec3Equal(t, 0, 0, 0, vt.Apply(BlankVec3(), pos), delta)
+	assertVec3Equal(t, 0, 0, -1, vt.Apply(BlankVec3(), BlankVec3().Add(pos, forward)), delta)
+	assertVec3Equal(t, 0, 1, 0, vt.Apply(BlankVec3(), BlankVec3().Add(pos, up)), delta)
+}
+
+func TestCameraOrientation(t *testing.T) {
+	forward := NewVec3(1, 2, 2)
+	forward.Scale(forward, 1./3)
+	up := NewVec3(2, 1, -2)
+	up.Scale(up, 1./3)
+	q := NewCameraOrientation(forward, up)
+	assertVec3Equal(t, forward[0], forward[1], forward[2], q.Apply(BlankVec3(), NewVec3(0, 0, -1)), 1e-12)
+	assertVec3Equal(t, up[0], up[1], up[2], q.Apply(BlankVec3(), NewVec3(0, 1, 0)), 1e-12)
+
+	q = NewCameraOrientation(NewVec3(0, 0, -1), NewVec3(0, 1, 0))
+	assertQuaternionEqual(t, IdentityQuaternion(), q, 1e-12)
+}
+
+func TestKeyframeCameraOrbit(t *testing.T) {
+	pr := NewOrthographic()
+	sc := &Screen{}
+	fz, fx := NewVec3(0, 0, -1), NewVec3(1, 0, 0)
+	uy := NewVec3(0, 1, 0)
+	keys := []*CameraKeyframe{
+		{Frame: 2, Pos: NewVec3(0, 0, 0), Orientation: NewCameraOrientation(fz, uy)},
+		{Frame: 6, Pos: NewVec3(4, 0, 0), Orientation: NewCameraOrientation(fx, uy)},
+		{Frame: 10, Pos: NewVec3(8, 0, 0), Orientation: NewCameraOrientation(fx, uy)},
+	}
+	ko, err := NewKeyframeCameraOrbit(keys, CatmullRomSpline, pr, sc)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, ko.Frames())
+
+	// Frames before the first keyframe hold its pose.
+	cam := ko.GetCamera(0)
+	assert.Same(t, pr, cam.Projector())
+	assert.Same(t, sc, cam.Screen())
+	assertCameraPose(t, keys[0].Pos, fz, uy, cam, 1e-12)
+
+	// Keyframe poses are reproduced exactly.
+	assertCameraPose(t, keys[0].Pos, fz, uy, ko.GetCamera(2), 1e-12)
+	assertCameraPose(t, keys[1].Pos, fx, uy, ko.GetCamera(6), 1e-12)
+	assertCameraPose(t, keys[2].Pos, fx, uy, ko.GetCamera(10), 1e-12)
+	// Frame index wraps around.
+	assertCameraPose(t, keys[0].Pos, fz, uy, ko.GetCamera(11), 1e-12)
+
+	// Collinear and evenly spaced keyframes move at constant speed, orientation halfway rotated.
+	f45 := NewVec3(1, 0, -1)
+	f45.Normalize(f45)
+	assertCameraPose(t, NewVec3(2, 0, 0), f45, uy, ko.GetCamera(4), 1e-12)
+	assertCameraPose(t, NewVec3(7, 0, 0), fx, uy, ko.GetCamera(9), 1e-12)
+}
+
+func TestKeyframeCameraOrbitHermite(t *testing.T) {
+	q := NewCameraOrientation(NewVec3(0, 0, -1), NewVec3(0, 1, 0))
+	keys := []*CameraKeyframe{
+		{Frame: 0, Pos: NewVec3(0, 0, 0), Orientation: q, Velocity: BlankVec3()},
+		{Frame: 4, Pos: NewVec3(0, 4, 0), Orientation: q, Velocity: BlankVec3()},
+	}
+	ko, err := NewKeyframeCameraOrbit(keys, HermiteSpline, NewOrthographic(), &Screen{})
+	assert.NoError(t, err)
+	// Zero velocities ease in and out: smoothstep 3s²-2s³.
+	f, u := NewVec3(0, 0, -1), NewVec3(0, 1, 0)
+	assertCameraPose(t, NewVec3(0, 4*(3./16-2./64), 0), f, u, ko.GetCamera(1), 1e-12)
+	assertCameraPose(t, NewVec3(0, 2, 0), f, u, ko.GetCamera(2), 1e-12)
+
+	// A non-zero velocity is followed at the keyframe, the Catmull-Rom estimate would be (0,1,0).
+	keys[0].Velocity = NewVec3(1, 0, 0)
+	keys[1].Velocity = nil
+	ko, err = NewKeyframeCameraOrbit(keys, HermiteSpline, NewOrthographic(), &Screen{})
+	assert.NoError(t, err)
+	// s=1/4: h10=9/64, h01=5/32, h11=-3/64, with segment tangents (4,0,0) and (0,4,0).
+	assertCameraPose(t, NewVec3(9./16, 4*5./32-3./16, 0), f, u, ko.GetCamera(1), 1e-12)
+
+	// Easing remaps the segment time: ease-in quad at s=1/2 evaluates the Catmull-Rom spline at 1/4.
+	keys[0].Velocity = nil
+	keys[0].Easing = EaseInQuad()
+	ko, err = NewKeyframeCameraOrbit(keys, CatmullRomSpline, NewOrthographic(), &Screen{})
+	assert.NoError(t, err)
+	assertCameraPose(t, NewVec3(0, 1, 0), f, u, ko.GetCamera(2), 1e-12)
+}
+
+func TestKeyframeCameraOrbitErrors(t *testing.T) {
+	pr, sc := NewOrthographic(), &Screen{}
+	q := IdentityQuaternion()
+	newKeys := func(f0, f1 int) []*CameraKeyframe {
+		return []*CameraKeyframe{
+			{Frame: f0, Pos: BlankVec3(), Orientation: q},
+			{Frame: f1, Pos: NewVec3(1, 0, 0), Orientation: q},
+		}
+	}
+	_, err := NewKeyframeCameraOrbit(nil, CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+	_, err = NewKeyframeCameraOrbit(newKeys(0, 1), CatmullRomSpline, nil, sc)
+	assert.Error(t, err)
+	_, err = NewKeyframeCameraOrbit(newKeys(0, 1), SplineKind(7), pr, sc)
+	assert.Error(t, err)
+	_, err = NewKeyframeCameraOrbit(newKeys(3, 3), CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+	_, err = NewKeyframeCameraOrbit(newKeys(-1, 3), CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+	keys := newKeys(0, 1)
+	keys[1].Orientation = NewQuaternion(1, 1, 0, 0)
+	_, err = NewKeyframeCameraOrbit(keys, CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+	keys = newKeys(0, 1)
+	keys[0].Pos = NewVec3(math.NaN(), 0, 0)
+	_, err = NewKeyframeCameraOrbit(keys, CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+	keys = newKeys(0, 1)
+	keys[0].Orientation = nil
+	_, err = NewKeyframeCameraOrbit(keys, CatmullRomSpline, pr, sc)
+	assert.Error(t, err)
+
+	// A single keyframe is a stationary orbit.
+	ko, err := NewKeyframeCameraOrbit(newKeys(0, 1)[1:], CatmullRomSpline, pr, sc)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ko.Frames())
+	assertCameraPose(t, NewVec3(1, 0, 0), NewVec3(0, 0, -1), NewVec3(0, 1, 0), ko.GetCamera(0), 1e-12)
+}
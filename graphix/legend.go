@@ -0,0 +1,58 @@
+package graphix
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// LegendEntry is a labelled color swatch of a legend.
+type LegendEntry struct {
+	Label string
+	Color color.Color
+}
+
+// NewLegendEntries pairs labels with colors, e.g., those returned by RandColors.
+// Caller is responsible for passing in the same number of labels and colors.
+func NewLegendEntries(labels []string, colors []color.NRGBA64) []LegendEntry {
+	entries := make([]LegendEntry, len(labels))
+	for i, l := range labels {
+		entries[i] = LegendEntry{Label: l, Color: colors[i]}
+	}
+	return entries
+}
+
+// LegendSettings defines a legend listing labelled color swatches one per line.
+type LegendSettings struct {
+	// Top-left corner of the legend on the image.
+	Origin  image.Point
+	Entries []LegendEntry
+	// Side length of the square swatches in pixels, zero means the font size.
+	SwatchSize int
+	Text       TextSettings
+}
+
+// DrawLegend draws the legend defined by settings on dst.
+func DrawLegend(dst draw.Image, settings LegendSettings) error {
+	if len(settings.Entries) == 0 {
+		return errors.New("legend entries must not be empty")
+	}
+	td := newTextDrawer(&settings.Text)
+	swatch := settings.SwatchSize
+	if swatch == 0 {
+		swatch = int(td.size)
+	}
+	lh := max(td.lineHeight(), swatch)
+	gap := max(swatch/2, 2)
+	for i, e := range settings.Entries {
+		if e.Color == nil {
+			return errors.New("legend entry color must not be nil")
+		}
+		y := settings.Origin.Y + i*(lh+gap)
+		sw := image.Rect(settings.Origin.X, y+(lh-swatch)/2, settings.Origin.X+swatch, y+(lh+swatch)/2)
+		draw.Draw(dst, sw, image.NewUniform(e.Color), image.Point{}, draw.Over)
+		td.draw(dst, e.Label, sw.Max.X+gap, y+(lh-td.lineHeight())/2)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package graphix
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawLegend(t *testing.T) {
+	img := newBlackImage(200, 100)
+	colors := []color.NRGBA64{{R: 0xffff, A: 0xffff}, {G: 0xffff, A: 0xffff}}
+	err := DrawLegend(img, LegendSettings{
+		Origin:     image.Pt(10, 10),
+		Entries:    NewLegendEntries([]string{"q = +1", "q = -1"}, colors),
+		SwatchSize: 10,
+	})
+	assert.NoError(t, err)
+	// Swatches are stacked vertically, followed by the labels.
+	lh := newTextDrawer(&TextSettings{}).lineHeight()
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, img.RGBAAt(15, 10+lh/2))
+	assert.Equal(t, color.RGBA{0, 255, 0, 255}, img.RGBAAt(15, 10+lh+5+lh/2))
+	assert.True(t, hasInk(img, image.Rect(25, 10, 80, 10+lh)))
+	assert.True(t, hasInk(img, image.Rect(25, 10+lh+5, 80, 10+2*lh+5)))
+	assert.False(t, hasInk(img, image.Rect(0, 10+2*lh+5, 200, 100)))
+	assert.False(t, hasInk(img, image.Rect(100, 0, 200, 100)))
+
+	assert.Error(t, DrawLegend(img, LegendSettings{}))
+	assert.Error(t, DrawLegend(img, LegendSettings{Entries: []LegendEntry{{Label: "x"}}}))
+}
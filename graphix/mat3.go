@@ -0,0 +1,90 @@
+package graphix
+
+import (
+	"errors"
+	"math"
+)
+
+// Mat3 represents a 3×3 matrix in row-major order, acting as a linear transform on column vectors.
+type Mat3 [3][3]float64
+
+var _ AffineTransform = (*Mat3)(nil)
+
+// IdentityMat3 returns the 3×3 identity matrix.
+func IdentityMat3() *Mat3 {
+	return &Mat3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// NewCopyMat3 returns a copy of m.
+func NewCopyMat3(m *Mat3) *Mat3 {
+	c := *m
+	return &c
+}
+
+// Apply applies m to u and stores the result into v then returns v.
+func (m *Mat3) Apply(v, u *Vec3) *Vec3 {
+	v[0], v[1], v[2] = m[0][0]*u[0]+m[0][1]*u[1]+m[0][2]*u[2],
+		m[1][0]*u[0]+m[1][1]*u[1]+m[1][2]*u[2],
+		m[2][0]*u[0]+m[2][1]*u[1]+m[2][2]*u[2]
+	return v
+}
+
+// Mat4 returns the homogeneous form of m.
+func (m *Mat3) Mat4() *Mat4 {
+	return &Mat4{
+		{m[0][0], m[0][1], m[0][2], 0},
+		{m[1][0], m[1][1], m[1][2], 0},
+		{m[2][0], m[2][1], m[2][2], 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Mul stores the product a·b into m then returns m. Applying m is thus equivalent to applying b then a.
+func (m *Mat3) Mul(a, b *Mat3) *Mat3 {
+	var r Mat3
+	for i := range 3 {
+		for j := range 3 {
+			r[i][j] = a[i][0]*b[0][j] + a[i][1]*b[1][j] + a[i][2]*b[2][j]
+		}
+	}
+	*m = r
+	return m
+}
+
+// Transpose stores the transpose of a into m then returns m.
+func (m *Mat3) Transpose(a *Mat3) *Mat3 {
+	m[0][1], m[1][0] = a[1][0], a[0][1]
+	m[0][2], m[2][0] = a[2][0], a[0][2]
+	m[1][2], m[2][1] = a[2][1], a[1][2]
+	m[0][0], m[1][1], m[2][2] = a[0][0], a[1][1], a[2][2]
+	return m
+}
+
+// Det returns the determinant of m.
+func (m *Mat3) Det() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Inverse stores the inverse of a into m then returns m.
+// An error is returned and m is left untouched if a is singular.
+func (m *Mat3) Inverse(a *Mat3) (*Mat3, error) {
+	det := a.Det()
+	if det == 0 || math.IsNaN(det) {
+		return nil, errors.New("matrix is singular")
+	}
+	inv := 1 / det
+	var r Mat3
+	r[0][0] = (a[1][1]*a[2][2] - a[1][2]*a[2][1]) * inv
+	r[0][1] = (a[0][2]*a[2][1] - a[0][1]*a[2][2]) * inv
+	r[0][2] = (a[0][1]*a[1][2] - a[0][2]*a[1][1]) * inv
+	r[1][0] = (a[1][2]*a[2][0] - a[1][0]*a[2][2]) * inv
+	r[1][1] = (a[0][0]*a[2][2] - a[0][2]*a[2][0]) * inv
+	r[1][2] = (a[0][2]*a[1][0] - a[0][0]*a[1][2]) * inv
+	r[2][0] = (a[1][0]*a[2][1] - a[1][1]*a[2][0]) * inv
+	r[2][1] = (a[0][1]*a[2][0] - a[0][0]*a[2][1]) * inv
+	r[2][2] = (a[0][0]*a[1][1] - a[0][1]*a[1][0]) * inv
+	*m = r
+	return m, nil
+}
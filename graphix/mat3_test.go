@@ -0,0 +1,62 @@
+package graphix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMat3Apply(t *testing.T) {
+	m := &Mat3{{0, -1, 0}, {1, 0, 0}, {0, 0, 2}}
+	verifyTransform(t, m, -2, 1, 6, 1, 2, 3, 1e-8)
+}
+
+func TestMat3Mul(t *testing.T) {
+	a := &Mat3{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}}
+	b := &Mat3{{2, 0, 0}, {0, 3, 0}, {0, 0, 4}}
+	m := &Mat3{}
+	assert.Same(t, m, m.Mul(a, b))
+	// Scale first, then rotate.
+	verifyTransform(t, m, -3, 2, 4, 1, 1, 1, 1e-8)
+
+	// In place op.
+	assert.Same(t, a, a.Mul(a, b))
+	assert.Equal(t, m, a)
+}
+
+func TestMat3Transpose(t *testing.T) {
+	a := &Mat3{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	m := &Mat3{}
+	assert.Same(t, m, m.Transpose(a))
+	assert.Equal(t, &Mat3{{1, 4, 7}, {2, 5, 8}, {3, 6, 9}}, m)
+
+	// In place op.
+	assert.Same(t, a, a.Transpose(a))
+	assert.Equal(t, m, a)
+}
+
+func TestMat3Inverse(t *testing.T) {
+	a := &Mat3{{2, 0, 1}, {1, 3, 0}, {0, 1, 4}}
+	assert.InDelta(t, 25, a.Det(), 1e-8)
+	m, err := (&Mat3{}).Inverse(a)
+	assert.NoError(t, err)
+	prod := (&Mat3{}).Mul(a, m)
+	for i := range 3 {
+		for j := range 3 {
+			exp := 0.0
+			if i == j {
+				exp = 1
+			}
+			assert.InDelta(t, exp, prod[i][j], 1e-8)
+		}
+	}
+
+	singular := &Mat3{{1, 2, 3}, {2, 4, 6}, {0, 1, 0}}
+	_, err = m.Inverse(singular)
+	assert.ErrorContains(t, err, "matrix is singular")
+}
+
+func TestMat3Mat4(t *testing.T) {
+	a := &Mat3{{0, -1, 0}, {1, 0, 0}, {0, 0, 2}}
+	verifyTransform(t, a.Mat4(), -2, 1, 6, 1, 2, 3, 1e-8)
+}
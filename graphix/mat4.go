@@ -0,0 +1,148 @@
+package graphix
+
+import (
+	"errors"
+	"math"
+)
+
+// AffineTransform is a Transform which also exposes its homogeneous matrix form.
+type AffineTransform interface {
+	Transform
+	// Mat4 returns the homogeneous matrix of the transform. The returned matrix is owned by the caller.
+	Mat4() *Mat4
+}
+
+// Mat4 represents a 4×4 homogeneous matrix in row-major order, acting on column vectors (x,y,z,1).
+type Mat4 [4][4]float64
+
+var _ AffineTransform = (*Mat4)(nil)
+
+// IdentityMat4 returns the 4×4 identity matrix.
+func IdentityMat4() *Mat4 {
+	return &Mat4{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// NewCopyMat4 returns a copy of m.
+func NewCopyMat4(m *Mat4) *Mat4 {
+	c := *m
+	return &c
+}
+
+// NewTranslation returns the matrix translating a point by d.
+func NewTranslation(d *Vec3) *Mat4 {
+	m := IdentityMat4()
+	m[0][3], m[1][3], m[2][3] = d[0], d[1], d[2]
+	return m
+}
+
+// NewScale returns the matrix scaling a point by sx, sy, sz along the x, y, z axis respectively.
+func NewScale(sx, sy, sz float64) *Mat4 {
+	m := IdentityMat4()
+	m[0][0], m[1][1], m[2][2] = sx, sy, sz
+	return m
+}
+
+// NewReflection returns the matrix reflecting a point about the plane through the origin with normal n.
+// Caller is responsible for passing in a normalized n.
+func NewReflection(n *Vec3) *Mat4 {
+	m := IdentityMat4()
+	for i := range 3 {
+		for j := range 3 {
+			m[i][j] -= 2 * n[i] * n[j]
+		}
+	}
+	return m
+}
+
+// Compose returns the matrix equivalent to applying ts in order, i.e., ts[0] first and ts[len(ts)-1] last.
+func Compose(ts ...AffineTransform) *Mat4 {
+	m := IdentityMat4()
+	for _, t := range ts {
+		m.Mul(t.Mat4(), m)
+	}
+	return m
+}
+
+// Inverse returns the matrix of the inverse transform of t, or an error if t is not invertible.
+func Inverse(t AffineTransform) (*Mat4, error) {
+	return t.Mat4().Inverse(t.Mat4())
+}
+
+// Apply applies m to u and stores the result into v then returns v.
+// If m is not affine, the result is divided by the homogeneous coordinate. A homogeneous coordinate of 0 means the
+// result is a point at infinity, whose components are then ±Inf, or NaN for the components which are 0.
+func (m *Mat4) Apply(v, u *Vec3) *Vec3 {
+	x := m[0][0]*u[0] + m[0][1]*u[1] + m[0][2]*u[2] + m[0][3]
+	y := m[1][0]*u[0] + m[1][1]*u[1] + m[1][2]*u[2] + m[1][3]
+	z := m[2][0]*u[0] + m[2][1]*u[1] + m[2][2]*u[2] + m[2][3]
+	w := m[3][0]*u[0] + m[3][1]*u[1] + m[3][2]*u[2] + m[3][3]
+	if w != 1 {
+		x, y, z = x/w, y/w, z/w
+	}
+	v[0], v[1], v[2] = x, y, z
+	return v
+}
+
+// Mat4 returns a copy of m.
+func (m *Mat4) Mat4() *Mat4 { return NewCopyMat4(m) }
+
+// Linear returns the upper-left 3×3 block of m.
+func (m *Mat4) Linear() *Mat3 {
+	return &Mat3{
+		{m[0][0], m[0][1], m[0][2]},
+		{m[1][0], m[1][1], m[1][2]},
+		{m[2][0], m[2][1], m[2][2]},
+	}
+}
+
+// Translation returns the translation column of m.
+func (m *Mat4) Translation() *Vec3 { return NewVec3(m[0][3], m[1][3], m[2][3]) }
+
+// Mul stores the product a·b into m then returns m. Applying m is thus equivalent to applying b then a.
+func (m *Mat4) Mul(a, b *Mat4) *Mat4 {
+	var r Mat4
+	for i := range 4 {
+		for j := range 4 {
+			r[i][j] = a[i][0]*b[0][j] + a[i][1]*b[1][j] + a[i][2]*b[2][j] + a[i][3]*b[3][j]
+		}
+	}
+	*m = r
+	return m
+}
+
+// Inverse stores the inverse of a into m then returns m.
+// An error is returned and m is left untouched if a is singular.
+func (m *Mat4) Inverse(a *Mat4) (*Mat4, error) {
+	// Gauss-Jordan elimination with partial pivoting on the augmented matrix [a|I].
+	l, r := *a, *IdentityMat4()
+	for c := range 4 {
+		p := c
+		for i := c + 1; i < 4; i++ {
+			if math.Abs(l[i][c]) > math.Abs(l[p][c]) {
+				p = i
+			}
+		}
+		if l[p][c] == 0 || math.IsNaN(l[p][c]) {
+			return nil, errors.New("matrix is singular")
+		}
+		l[c], l[p] = l[p], l[c]
+		r[c], r[p] = r[p], r[c]
+		inv := 1 / l[c][c]
+		for j := range 4 {
+			l[c][j] *= inv
+			r[c][j] *= inv
+		}
+		for i := range 4 {
+			if i == c || l[i][c] == 0 {
+				continue
+			}
+			f := l[i][c]
+			for j := range 4 {
+				l[i][j] -= f * l[c][j]
+				r[i][j] -= f * r[c][j]
+			}
+		}
+	}
+	*m = r
+	return m, nil
+}
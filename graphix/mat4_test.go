@@ -0,0 +1,86 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertMat4Equal(t *testing.T, exp, m *Mat4, delta float64) {
+	for i := range 4 {
+		for j := range 4 {
+			assert.InDelta(t, exp[i][j], m[i][j], delta)
+		}
+	}
+}
+
+func TestMat4Apply(t *testing.T) {
+	verifyTransform(t, IdentityMat4(), 1, 2, 3, 1, 2, 3, 1e-8)
+
+	// Non-affine matrix divides by the homogeneous coordinate.
+	m := IdentityMat4()
+	m[3][3] = 2
+	verifyTransform(t, m, .5, 1, 1.5, 1, 2, 3, 1e-8)
+
+	// A point at infinity.
+	m[3][3], m[3][2] = 0, 1
+	v := m.Apply(BlankVec3(), NewVec3(1, -2, 0))
+	assert.Equal(t, math.Inf(1), v[0])
+	assert.Equal(t, math.Inf(-1), v[1])
+	assert.True(t, math.IsNaN(v[2]))
+}
+
+func TestTranslation(t *testing.T) {
+	tr := NewTranslation(NewVec3(1, -2, 3))
+	verifyTransform(t, tr, 2, 0, 6, 1, 2, 3, 1e-8)
+	assertVec3Equal(t, 1, -2, 3, tr.Translation(), 1e-8)
+}
+
+func TestScale(t *testing.T) {
+	verifyTransform(t, NewScale(2, 3, -1), 2, 6, -3, 1, 2, 3, 1e-8)
+}
+
+func TestReflection(t *testing.T) {
+	n := NewVec3(1, 1, 0)
+	ref := NewReflection(n.Normalize(n))
+	verifyTransform(t, ref, -2, -1, 3, 1, 2, 3, 1e-8)
+	// Reflecting twice is identity.
+	assertMat4Equal(t, IdentityMat4(), (&Mat4{}).Mul(ref, ref), 1e-8)
+}
+
+func TestCompose(t *testing.T) {
+	// Scale, then rotate around z by π/2, then translate.
+	m := Compose(
+		NewScale(2, 2, 2),
+		NewAxisAngleRotation(NewVec3(0, 0, 1), math.Pi/2),
+		NewTranslation(NewVec3(0, 0, 1)),
+	)
+	verifyTransform(t, m, -4, 2, 7, 1, 2, 3, 1e-8)
+
+	assertMat4Equal(t, IdentityMat4(), Compose(), 0)
+}
+
+func TestMat4Inverse(t *testing.T) {
+	vt := NewViewTransform(NewVec3(1, 1, 0), NewVec3(0, 0, 1), NewVec3(1/math.Sqrt(2), 1/math.Sqrt(2), 0))
+	m := Compose(NewScale(1, 2, 3), vt)
+	inv, err := Inverse(m)
+	assert.NoError(t, err)
+	assertMat4Equal(t, IdentityMat4(), (&Mat4{}).Mul(inv, m), 1e-8)
+	assertMat4Equal(t, IdentityMat4(), (&Mat4{}).Mul(m, inv), 1e-8)
+
+	// In place op.
+	res, err := m.Inverse(m)
+	assert.NoError(t, err)
+	assert.Same(t, m, res)
+	assertMat4Equal(t, inv, m, 1e-8)
+
+	_, err = Inverse(NewScale(1, 0, 1))
+	assert.ErrorContains(t, err, "matrix is singular")
+}
+
+func TestMat4Linear(t *testing.T) {
+	m := Compose(NewAxisAngleRotation(NewVec3(0, 0, 1), math.Pi/2), NewTranslation(NewVec3(1, 2, 3)))
+	verifyTransform(t, m.Linear(), -2, 1, 3, 1, 2, 3, 1e-8)
+	assertVec3Equal(t, 1, 2, 3, m.Translation(), 1e-8)
+}
@@ -0,0 +1,46 @@
+package graphix
+
+import "math"
+
+// Defines an oblique projector, where the receding depth axis is drawn at an angle on the projection plane.
+type oblique struct {
+	// Displacement on the projection plane per unit of depth.
+	dx float64
+	dy float64
+	// Depth of the projection plane, where points are not displaced.
+	d0 float64
+}
+
+var (
+	_ Projector   = (*oblique)(nil)
+	_ Unprojector = (*oblique)(nil)
+)
+
+// NewOblique returns an oblique projector with respect to the canonical camera position.
+// A point at depth d (i.e., z=-d) is displaced on the projection plane by ratio*(d-d0) along the direction
+// at angle (counterclockwise from +x), so the receding -z axis is drawn at angle and foreshortened by ratio.
+// The depth of the point is kept in the projection, so depth sorting works as in NewOrthographic.
+func NewOblique(angle, ratio, d0 float64) Projector {
+	s, c := math.Sincos(angle)
+	return &oblique{dx: ratio * c, dy: ratio * s, d0: d0}
+}
+
+// NewCavalier returns an oblique projector in which the receding axis is drawn at angle without foreshortening.
+func NewCavalier(angle, d0 float64) Projector { return NewOblique(angle, 1, d0) }
+
+// NewCabinet returns an oblique projector in which the receding axis is drawn at angle with half of its length.
+func NewCabinet(angle, d0 float64) Projector { return NewOblique(angle, .5, d0) }
+
+func (*oblique) NearZClip() float64 { return 0 }
+
+func (ob *oblique) Project(p *Projection, v *Vec3) *Projection {
+	d := -v[2]
+	p[0], p[1], p[2] = v[0]+ob.dx*(d-ob.d0), v[1]+ob.dy*(d-ob.d0), d
+	return p
+}
+
+func (ob *oblique) Unproject(v *Vec3, p *Projection) *Vec3 {
+	d := p[2]
+	v[0], v[1], v[2] = p[0]-ob.dx*(d-ob.d0), p[1]-ob.dy*(d-ob.d0), -d
+	return v
+}
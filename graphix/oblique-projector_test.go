@@ -0,0 +1,33 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObliqueProjector(t *testing.T) {
+	ob := NewOblique(math.Pi/2, 2, 5)
+	assert.Equal(t, 0.0, ob.NearZClip())
+	_, ok := ob.(FarClipper)
+	assert.False(t, ok)
+
+	p := BlankProjection()
+	// On the projection plane, no displacement.
+	assert.Same(t, p, ob.Project(p, NewVec3(1, 2, -5)))
+	assertProjectionEqual(t, 1, 2, 5, p, 1e-8)
+	// Receding by 1 unit moves up by 2 units.
+	assertProjectionEqual(t, 1, 4, 6, ob.Project(p, NewVec3(1, 2, -6)), 1e-8)
+	verifyUnproject(t, ob, 1, -2, 3, 1e-8)
+}
+
+func TestCavalierProjector(t *testing.T) {
+	cav := NewCavalier(math.Pi/4, 0)
+	assertProjectionEqual(t, 1+1/math.Sqrt(2), 1/math.Sqrt(2), 1, cav.Project(BlankProjection(), NewVec3(1, 0, -1)), 1e-8)
+}
+
+func TestCabinetProjector(t *testing.T) {
+	cab := NewCabinet(math.Pi/6, 2)
+	assertProjectionEqual(t, math.Sqrt(3)/2, .5, 4, cab.Project(BlankProjection(), NewVec3(0, 0, -4)), 1e-8)
+}
@@ -0,0 +1,113 @@
+package graphix
+
+import (
+	"math"
+	"sort"
+)
+
+// All combinators below wrap the frame index around modulo their Frames(), like the other CameraOrbit implementations.
+
+type concatCameraOrbit struct {
+	orbits []CameraOrbit
+	// starts[i] is the first frame of orbits[i], with starts[len(orbits)] being the total number of frames.
+	starts []int
+}
+
+var _ CameraOrbit = (*concatCameraOrbit)(nil)
+
+// ConcatCameraOrbits returns a CameraOrbit playing orbits one after another.
+// Caller is responsible for passing in at least one orbit with positive frames.
+func ConcatCameraOrbits(orbits ...CameraOrbit) CameraOrbit {
+	co := &concatCameraOrbit{
+		orbits: append([]CameraOrbit(nil), orbits...),
+		starts: make([]int, len(orbits)+1),
+	}
+	for i, o := range orbits {
+		co.starts[i+1] = co.starts[i] + o.Frames()
+	}
+	return co
+}
+
+func (co *concatCameraOrbit) Frames() int { return co.starts[len(co.orbits)] }
+
+func (co *concatCameraOrbit) GetCamera(i int) *Camera {
+	i = i % co.Frames()
+	// Index of the orbit whose frames contain i, skipping orbits without frames.
+	n := sort.Search(len(co.orbits), func(j int) bool { return co.starts[j+1] > i })
+	return co.orbits[n].GetCamera(i - co.starts[n])
+}
+
+type reverseCameraOrbit struct {
+	o CameraOrbit
+}
+
+var _ CameraOrbit = (*reverseCameraOrbit)(nil)
+
+// ReverseCameraOrbit returns a CameraOrbit playing o backwards, from its last frame to its first.
+func ReverseCameraOrbit(o CameraOrbit) CameraOrbit { return &reverseCameraOrbit{o: o} }
+
+func (ro *reverseCameraOrbit) Frames() int { return ro.o.Frames() }
+
+func (ro *reverseCameraOrbit) GetCamera(i int) *Camera {
+	n := ro.o.Frames()
+	return ro.o.GetCamera(n - 1 - i%n)
+}
+
+type pingPongCameraOrbit struct {
+	o CameraOrbit
+}
+
+var _ CameraOrbit = (*pingPongCameraOrbit)(nil)
+
+// PingPongCameraOrbit returns a CameraOrbit playing o forwards then backwards.
+// The turning frames are not repeated, so an orbit of n>1 frames results in 2n-2 frames which loop seamlessly.
+func PingPongCameraOrbit(o CameraOrbit) CameraOrbit { return &pingPongCameraOrbit{o: o} }
+
+func (po *pingPongCameraOrbit) Frames() int {
+	if n := po.o.Frames(); n > 1 {
+		return 2*n - 2
+	}
+	return po.o.Frames()
+}
+
+func (po *pingPongCameraOrbit) GetCamera(i int) *Camera {
+	i = i % po.Frames()
+	if n := po.o.Frames(); i >= n {
+		i = 2*n - 2 - i
+	}
+	return po.o.GetCamera(i)
+}
+
+// HoldCameraOrbit returns a CameraOrbit of frames frames, all holding the camera of o at frame f.
+func HoldCameraOrbit(o CameraOrbit, f, frames int) CameraOrbit {
+	return NewStationaryCamera(o.GetCamera(f), frames)
+}
+
+type retimeCameraOrbit struct {
+	o      CameraOrbit
+	e      Easing
+	frames int
+}
+
+var _ CameraOrbit = (*retimeCameraOrbit)(nil)
+
+// RetimeCameraOrbit returns a CameraOrbit of frames frames spanning the whole of o, whose frame i maps to the frame
+// of o nearest to e(i/(frames-1))·(o.Frames()-1). The first and last frames thus map to the first and last frames
+// of o, and o is played slower or faster when frames is larger or smaller than o.Frames().
+// Caller is responsible for passing in a positive frames and an e mapping [0,1] into [0,1].
+func RetimeCameraOrbit(o CameraOrbit, e Easing, frames int) CameraOrbit {
+	return &retimeCameraOrbit{o: o, e: e, frames: frames}
+}
+
+func (rt *retimeCameraOrbit) Frames() int { return rt.frames }
+
+func (rt *retimeCameraOrbit) GetCamera(i int) *Camera {
+	i = i % rt.frames
+	t := 0.0
+	if rt.frames > 1 {
+		t = float64(i) / float64(rt.frames-1)
+	}
+	n := rt.o.Frames()
+	f := int(math.Round(rt.e.Ease(t) * float64(n-1)))
+	return rt.o.GetCamera(min(max(f, 0), n-1))
+}
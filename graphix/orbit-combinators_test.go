@@ -0,0 +1,69 @@
+package graphix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCameraOrbit records the frame index in the returned camera's screen width.
+type testCameraOrbit struct {
+	offset int
+	frames int
+}
+
+func (to *testCameraOrbit) Frames() int { return to.frames }
+
+func (to *testCameraOrbit) GetCamera(i int) *Camera {
+	return NewCamera(nil, nil, &Screen{width: to.offset + i%to.frames})
+}
+
+func assertOrbitFrames(t *testing.T, exp []int, o CameraOrbit) {
+	assert.Equal(t, len(exp), o.Frames())
+	for i, e := range exp {
+		assert.Equal(t, e, o.GetCamera(i).Screen().Width(), "frame %v", i)
+	}
+	// Wraps around.
+	assert.Equal(t, exp[0], o.GetCamera(len(exp)).Screen().Width())
+}
+
+func TestConcatCameraOrbits(t *testing.T) {
+	o := ConcatCameraOrbits(
+		&testCameraOrbit{offset: 10, frames: 2},
+		&testCameraOrbit{offset: 20, frames: 0},
+		&testCameraOrbit{offset: 30, frames: 3},
+	)
+	assertOrbitFrames(t, []int{10, 11, 30, 31, 32}, o)
+}
+
+func TestReverseCameraOrbit(t *testing.T) {
+	assertOrbitFrames(t, []int{3, 2, 1, 0}, ReverseCameraOrbit(&testCameraOrbit{frames: 4}))
+}
+
+func TestPingPongCameraOrbit(t *testing.T) {
+	assertOrbitFrames(t, []int{0, 1, 2, 3, 2, 1}, PingPongCameraOrbit(&testCameraOrbit{frames: 4}))
+	assertOrbitFrames(t, []int{0}, PingPongCameraOrbit(&testCameraOrbit{frames: 1}))
+}
+
+func TestHoldCameraOrbit(t *testing.T) {
+	assertOrbitFrames(t, []int{2, 2, 2}, HoldCameraOrbit(&testCameraOrbit{frames: 4}, 2, 3))
+}
+
+func TestRetimeCameraOrbit(t *testing.T) {
+	o := &testCameraOrbit{frames: 5}
+	assertOrbitFrames(t, []int{0, 1, 2, 3, 4}, RetimeCameraOrbit(o, LinearEasing(), 5))
+	// Slowed down.
+	assertOrbitFrames(t, []int{0, 1, 1, 2, 2, 3, 3, 4, 4}, RetimeCameraOrbit(o, LinearEasing(), 9))
+	// Sped up.
+	assertOrbitFrames(t, []int{0, 2, 4}, RetimeCameraOrbit(o, LinearEasing(), 3))
+	// Eased: 4·(0, 1/16, 1/4, 9/16, 1).
+	assertOrbitFrames(t, []int{0, 0, 1, 2, 4}, RetimeCameraOrbit(o, EaseInQuad(), 5))
+	assertOrbitFrames(t, []int{0}, RetimeCameraOrbit(o, LinearEasing(), 1))
+}
+
+func TestComposedCameraOrbit(t *testing.T) {
+	intro := NewStationaryCamera(NewCamera(nil, nil, &Screen{width: 100}), 2)
+	sweep := &testCameraOrbit{frames: 3}
+	o := ConcatCameraOrbits(intro, sweep, HoldCameraOrbit(sweep, 2, 2))
+	assertOrbitFrames(t, []int{100, 100, 0, 1, 2, 2, 2}, o)
+}
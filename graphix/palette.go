@@ -0,0 +1,124 @@
+package graphix
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// PaletteSettings defines the constraints of a palette generated by GeneratePalette.
+type PaletteSettings struct {
+	// Number of colors in the palette.
+	N int
+	// Range of the OKLab lightness of the colors, zero MaxLightness means 1.
+	MinLightness float64
+	MaxLightness float64
+	// Minimum OKLCH chroma of the colors, which excludes grayish colors.
+	MinChroma float64
+	// The colors are also kept distinct as seen with each of these deficiencies.
+	ColorVisionDeficiencies []ColorVisionDeficiency
+	// Colors to keep distinct from, e.g., the background color, which are not part of the palette.
+	Avoid []color.Color
+	// Number of random sRGB candidates to choose the colors from, zero means 4096.
+	Candidates int
+	// Source of randomness of the candidates. If nil, a source seeded with Seed is used.
+	// The same source state or seed always generates the same palette.
+	Source rand.Source
+	Seed   int64
+}
+
+// GeneratePalette returns a palette of settings.N colors which are perceptually distinct from each other and from
+// settings.Avoid. The colors are greedily picked from random candidates satisfying the constraints in settings,
+// each maximizing its minimal OKLab distance to the colors picked before, so that any prefix of the palette is also
+// well spread. The distance between two colors is the minimum of their distances as seen with normal vision and
+// with each of settings.ColorVisionDeficiencies.
+// An error is returned if the settings are invalid, or too few candidates satisfy the constraints.
+func GeneratePalette(settings PaletteSettings) ([]color.NRGBA64, error) {
+	if settings.N <= 0 {
+		return nil, errors.New("number of colors must be positive")
+	}
+	maxL := settings.MaxLightness
+	if maxL == 0 {
+		maxL = 1
+	}
+	if settings.MinLightness < 0 || maxL > 1 || settings.MinLightness > maxL {
+		return nil, fmt.Errorf("invalid lightness range [%v,%v]", settings.MinLightness, maxL)
+	}
+	for _, cvd := range settings.ColorVisionDeficiencies {
+		if _, ok := colorVisionMatrices[cvd]; !ok {
+			return nil, fmt.Errorf("unknown color vision deficiency %d", int(cvd))
+		}
+	}
+	cnt := settings.Candidates
+	if cnt == 0 {
+		cnt = 4096
+	}
+	src := settings.Source
+	if src == nil {
+		src = rand.NewSource(settings.Seed)
+	}
+	rng := rand.New(src)
+
+	views := len(settings.ColorVisionDeficiencies) + 1
+	// Returns the OKLab coordinates of c as seen with normal vision followed by each deficiency.
+	seen := func(c color.NRGBA64) []OKLab {
+		labs := make([]OKLab, views)
+		labs[0] = NewOKLab(c)
+		for i, cvd := range settings.ColorVisionDeficiencies {
+			labs[i+1] = NewOKLab(SimulateColorVision(c, cvd))
+		}
+		return labs
+	}
+	type candidate struct {
+		c    color.NRGBA64
+		labs []OKLab
+		// Minimal distance to the picked and avoided colors.
+		dist float64
+	}
+	var candidates []*candidate
+	for range cnt {
+		c := color.NRGBA64{
+			R: uint16(rng.Intn(math.MaxUint16 + 1)),
+			G: uint16(rng.Intn(math.MaxUint16 + 1)),
+			B: uint16(rng.Intn(math.MaxUint16 + 1)),
+			A: math.MaxUint16,
+		}
+		lch := NewOKLCH(c)
+		if lch.L < settings.MinLightness || lch.L > maxL || lch.C < settings.MinChroma {
+			continue
+		}
+		candidates = append(candidates, &candidate{c: c, labs: seen(c), dist: math.Inf(1)})
+	}
+	if len(candidates) < settings.N {
+		return nil, fmt.Errorf("only %v of %v candidates satisfy the constraints, need %v", len(candidates), cnt, settings.N)
+	}
+
+	// Lowers the candidates' distances by a newly picked or avoided color.
+	update := func(labs []OKLab) {
+		for _, cand := range candidates {
+			for i := range views {
+				cand.dist = math.Min(cand.dist, cand.labs[i].Distance(labs[i]))
+			}
+		}
+	}
+	for _, c := range settings.Avoid {
+		update(seen(color.NRGBA64Model.Convert(c).(color.NRGBA64)))
+	}
+	palette := make([]color.NRGBA64, 0, settings.N)
+	for range settings.N {
+		best := 0
+		for i, cand := range candidates {
+			if cand.dist > candidates[best].dist {
+				best = i
+			}
+		}
+		picked := candidates[best]
+		palette = append(palette, picked.c)
+		candidates[best] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+		update(picked.labs)
+	}
+	return palette, nil
+}
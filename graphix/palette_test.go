@@ -0,0 +1,92 @@
+package graphix
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the minimal pairwise distance of colors as seen with cvds and normal vision.
+func minPaletteDistance(colors []color.NRGBA64, cvds ...ColorVisionDeficiency) float64 {
+	d := math.Inf(1)
+	for i := range colors {
+		for j := range i {
+			d = math.Min(d, NewOKLab(colors[i]).Distance(NewOKLab(colors[j])))
+			for _, cvd := range cvds {
+				d = math.Min(d, NewOKLab(SimulateColorVision(colors[i], cvd)).Distance(
+					NewOKLab(SimulateColorVision(colors[j], cvd))))
+			}
+		}
+	}
+	return d
+}
+
+func TestGeneratePalette(t *testing.T) {
+	settings := PaletteSettings{
+		N:            8,
+		MinLightness: .4,
+		MaxLightness: .9,
+		MinChroma:    .05,
+		Avoid:        []color.Color{color.Black},
+		Seed:         42,
+	}
+	p1, err := GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.Len(t, p1, 8)
+	for _, c := range p1 {
+		lch := NewOKLCH(c)
+		assert.GreaterOrEqual(t, lch.L, .4)
+		assert.LessOrEqual(t, lch.L, .9)
+		assert.GreaterOrEqual(t, lch.C, .05)
+	}
+	// Reproducible.
+	p2, err := GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.Equal(t, p1, p2)
+	settings.Source = rand.NewSource(42)
+	p2, err = GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.Equal(t, p1, p2)
+	settings.Source = rand.NewSource(7)
+	p2, err = GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.NotEqual(t, p1, p2)
+
+	// Much better spread than random colors.
+	rng := rand.New(rand.NewSource(1))
+	random := RandColorsFrom(rng, 8)
+	assert.Greater(t, minPaletteDistance(p1), minPaletteDistance(random))
+
+	// Colorblind-safe palettes are distinct with the deficiency.
+	settings.Source = nil
+	settings.ColorVisionDeficiencies = []ColorVisionDeficiency{Deuteranopia}
+	safe, err := GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.Greater(t, minPaletteDistance(safe, Deuteranopia), minPaletteDistance(p1, Deuteranopia))
+	// Prefixes are well spread too.
+	settings.N = 3
+	prefix, err := GeneratePalette(settings)
+	assert.NoError(t, err)
+	assert.Equal(t, safe[:3], prefix)
+}
+
+func TestGeneratePaletteErrors(t *testing.T) {
+	_, err := GeneratePalette(PaletteSettings{})
+	assert.Error(t, err)
+	_, err = GeneratePalette(PaletteSettings{N: 2, MinLightness: .8, MaxLightness: .2})
+	assert.Error(t, err)
+	_, err = GeneratePalette(PaletteSettings{N: 2, ColorVisionDeficiencies: []ColorVisionDeficiency{-1}})
+	assert.Error(t, err)
+	_, err = GeneratePalette(PaletteSettings{N: 2, MinChroma: 1})
+	assert.ErrorContains(t, err, "satisfy the constraints")
+}
+
+func TestRandColorsFrom(t *testing.T) {
+	c1 := RandColorsFrom(rand.New(rand.NewSource(3)), 5)
+	c2 := RandColorsFrom(rand.New(rand.NewSource(3)), 5)
+	assert.Equal(t, c1, c2)
+	assert.Equal(t, RandColorFrom(rand.New(rand.NewSource(3))), RandColorFrom(rand.New(rand.NewSource(3))))
+}
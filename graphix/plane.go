@@ -0,0 +1,45 @@
+package graphix
+
+// Plane represents the plane of points x satisfying N·x=D, where N is the unit normal.
+// As a Volume, it is the closed half-space on the opposite side of N, i.e., N·x≤D.
+type Plane struct {
+	N *Vec3
+	D float64
+}
+
+var _ Volume = (*Plane)(nil)
+
+// NewPlane creates a Plane with normal n and offset d.
+// Caller is responsible for passing in a normalized n.
+func NewPlane(n *Vec3, d float64) *Plane {
+	return &Plane{N: NewCopyVec3(n), D: d}
+}
+
+// NewPointNormalPlane creates a Plane passing through p with normal n.
+// Caller is responsible for passing in a normalized n.
+func NewPointNormalPlane(p, n *Vec3) *Plane {
+	return &Plane{N: NewCopyVec3(n), D: n.Dot(p)}
+}
+
+func (pl *Plane) Contains(p *Vec3) bool { return pl.SignedDistance(p) <= 0 }
+
+func (pl *Plane) SignedDistance(p *Vec3) float64 { return pl.N.Dot(p) - pl.D }
+
+func (pl *Plane) ClosestPoint(v, p *Vec3) *Vec3 {
+	sd := pl.SignedDistance(p)
+	v[0], v[1], v[2] = p[0]-sd*pl.N[0], p[1]-sd*pl.N[1], p[2]-sd*pl.N[2]
+	return v
+}
+
+func (pl *Plane) IntersectRay(r *Ray) (float64, bool) {
+	den := pl.N.Dot(r.Dir)
+	// Parallel to the plane.
+	if den == 0 {
+		return 0, false
+	}
+	t := -pl.SignedDistance(r.Origin) / den
+	if t < 0 {
+		return 0, false
+	}
+	return t, true
+}
@@ -0,0 +1,36 @@
+package graphix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlane(t *testing.T) {
+	pl := NewPointNormalPlane(NewVec3(0, 0, 2), NewVec3(0, 0, 1))
+	assert.InDelta(t, 2, pl.D, 1e-8)
+	assert.InDelta(t, 3, pl.SignedDistance(NewVec3(1, 1, 5)), 1e-8)
+	assert.InDelta(t, -1, pl.SignedDistance(NewVec3(1, 1, 1)), 1e-8)
+	assert.True(t, pl.Contains(NewVec3(1, 1, 1)))
+	assert.True(t, pl.Contains(NewVec3(1, 1, 2)))
+	assert.False(t, pl.Contains(NewVec3(1, 1, 3)))
+
+	v := BlankVec3()
+	assert.Same(t, v, pl.ClosestPoint(v, NewVec3(1, 1, 5)))
+	assertVec3Equal(t, 1, 1, 2, v, 1e-8)
+}
+
+func TestPlaneIntersectRay(t *testing.T) {
+	pl := NewPlane(NewVec3(0, 0, 1), 2)
+	tt, ok := pl.IntersectRay(NewRay(NewVec3(0, 0, 0), NewVec3(1, 0, 1)))
+	assert.True(t, ok)
+	assert.InDelta(t, 2, tt, 1e-8)
+
+	// Pointing away.
+	_, ok = pl.IntersectRay(NewRay(NewVec3(0, 0, 0), NewVec3(0, 0, -1)))
+	assert.False(t, ok)
+
+	// Parallel.
+	_, ok = pl.IntersectRay(NewRay(NewVec3(0, 0, 0), NewVec3(1, 0, 0)))
+	assert.False(t, ok)
+}
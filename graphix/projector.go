@@ -1,5 +1,10 @@
 package graphix
 
+import (
+	"fmt"
+	"math"
+)
+
 // Projection defines a projected Vec3. Its [0] and [1] are the two coordinates (e.g., x and y)
 // in the projected plane, while its [2] keeps the z-distance of the original Vec3 with respect to the camera
 // (orthographic or perspective).
@@ -9,17 +14,44 @@ func BlankProjection() *Projection                 { return NewProjection(0, 0,
 func NewProjection(v0, v1, v2 float64) *Projection { return &Projection{v0, v1, v2} }
 
 // Projector defines an interface projecting a Vec3 into a Projection.
+// Line segments crossing a z-clip plane are clipped by interpolating the projected coordinates linearly, which is
+// only exact if they are affine in the view-space coordinates. Other projectors must implement PerspectiveProjector.
 type Projector interface {
 	// The "near" clipping plane's z-coordinate. Points nearer than this plane shall not produce visible projection.
 	NearZClip() float64
 	Project(p *Projection, v *Vec3) *Projection
 }
 
+// Unprojector is optionally implemented by a Projector which can be inverted.
+type Unprojector interface {
+	// Unproject is the inverse of Project. It stores into v the point whose projection is p (including the
+	// z-distance p[2]) then returns v.
+	Unproject(v *Vec3, p *Projection) *Vec3
+}
+
+// FarClipper is optionally implemented by a Projector with a "far" clipping plane.
+// A Projector not implementing it has no far clipping plane.
+type FarClipper interface {
+	// The "far" clipping plane's z-coordinate. Points farther than this plane shall not produce visible projection.
+	FarZClip() float64
+}
+
+// PerspectiveProjector is optionally implemented by a Projector whose projected coordinates are not affine in the
+// view-space coordinates, e.g., projecting from the camera position.
+type PerspectiveProjector interface {
+	// Perspective reports whether points clipped at a z-clip plane must be projected again from view space, instead
+	// of interpolating the projected coordinates linearly.
+	Perspective() bool
+}
+
 // Defines an orthographic projector with respect to the canonical camera position, i.e.,
 // the camera is positioned at origin, forward is -z, up is +y.
 type orthographic struct{}
 
-var _ Projector = (*orthographic)(nil)
+var (
+	_ Projector   = (*orthographic)(nil)
+	_ Unprojector = (*orthographic)(nil)
+)
 
 func NewOrthographic() Projector { return &orthographic{} }
 
@@ -31,12 +63,21 @@ func (*orthographic) Project(p *Projection, v *Vec3) *Projection {
 	return p
 }
 
+func (*orthographic) Unproject(v *Vec3, p *Projection) *Vec3 {
+	v[0], v[1], v[2] = p[0], p[1], -p[2]
+	return v
+}
+
 // Defines a perspective projector.
 type perspective struct {
 	d float64
 }
 
-var _ Projector = (*perspective)(nil)
+var (
+	_ Projector            = (*perspective)(nil)
+	_ PerspectiveProjector = (*perspective)(nil)
+	_ Unprojector          = (*perspective)(nil)
+)
 
 // NewPerspective returns a perspective projector with respect to the canonical camera position, i.e.,
 // the camera is positioned at origin, forward is -z, up is +y.
@@ -46,9 +87,77 @@ func NewPerspective(dist float64) Projector {
 }
 
 func (per *perspective) NearZClip() float64 { return per.d / 5 }
+func (*perspective) Perspective() bool      { return true }
 
 func (per *perspective) Project(p *Projection, v *Vec3) *Projection {
 	ratio := -per.d / v[2]
 	p[0], p[1], p[2] = v[0]*ratio, v[1]*ratio, -v[2]
 	return p
 }
+
+func (per *perspective) Unproject(v *Vec3, p *Projection) *Vec3 {
+	ratio := p[2] / per.d
+	v[0], v[1], v[2] = p[0]*ratio, p[1]*ratio, -p[2]
+	return v
+}
+
+// Defines a perspective projector from field of view, which maps the visible frustum into [-1,1]×[-1,1].
+type fovPerspective struct {
+	// Half extent of the frustum per unit of z-distance, horizontally and vertically.
+	tanX float64
+	tanY float64
+	near float64
+	far  float64
+}
+
+var (
+	_ Projector            = (*fovPerspective)(nil)
+	_ FarClipper           = (*fovPerspective)(nil)
+	_ PerspectiveProjector = (*fovPerspective)(nil)
+	_ Unprojector          = (*fovPerspective)(nil)
+)
+
+// NewFOVPerspective returns a perspective projector with respect to the canonical camera position, with vertical
+// field of view fovY (in radians), aspect ratio (width over height), and near and far clipping planes at z-distance
+// near and far respectively.
+// Points within the field of view are projected into [-1,1]×[-1,1], see NewFOVScreen.
+func NewFOVPerspective(fovY, aspect, near, far float64) Projector {
+	tanY := math.Tan(fovY / 2)
+	return &fovPerspective{
+		tanX: tanY * aspect,
+		tanY: tanY,
+		near: near,
+		far:  far,
+	}
+}
+
+// NewCheckedFOVPerspective is like NewFOVPerspective, but returns an error if fovY is not in (0,π), if aspect is not
+// positive and finite, or unless 0<near<far (far may be +Inf).
+func NewCheckedFOVPerspective(fovY, aspect, near, far float64) (Projector, error) {
+	if !(fovY > 0 && fovY < math.Pi) {
+		return nil, fmt.Errorf("field of view must be in (0,π), got %v", fovY)
+	}
+	if !(aspect > 0 && !math.IsInf(aspect, 1)) {
+		return nil, fmt.Errorf("aspect ratio must be positive and finite, got %v", aspect)
+	}
+	if !(near > 0 && near < far) {
+		return nil, fmt.Errorf("clipping planes must satisfy 0<near<far, got near %v and far %v", near, far)
+	}
+	return NewFOVPerspective(fovY, aspect, near, far), nil
+}
+
+func (per *fovPerspective) NearZClip() float64 { return per.near }
+func (per *fovPerspective) FarZClip() float64  { return per.far }
+func (*fovPerspective) Perspective() bool      { return true }
+
+func (per *fovPerspective) Project(p *Projection, v *Vec3) *Projection {
+	d := -v[2]
+	p[0], p[1], p[2] = v[0]/(d*per.tanX), v[1]/(d*per.tanY), d
+	return p
+}
+
+func (per *fovPerspective) Unproject(v *Vec3, p *Projection) *Vec3 {
+	d := p[2]
+	v[0], v[1], v[2] = p[0]*d*per.tanX, p[1]*d*per.tanY, -d
+	return v
+}
@@ -1,6 +1,7 @@
 package graphix
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,18 +10,68 @@ import (
 func TestOrthographicProjector(t *testing.T) {
 	o := NewOrthographic()
 	assert.Equal(t, 0.0, o.NearZClip())
+	_, ok := o.(FarClipper)
+	assert.False(t, ok)
+	_, ok = o.(PerspectiveProjector)
+	assert.False(t, ok)
 	v := NewVec3(3, 5, -2)
 	p := BlankProjection()
 	assert.Same(t, p, o.Project(p, v))
 	assertProjectionEqual(t, 3, 5, 2, p, 1e-8)
+	verifyUnproject(t, o, 3, 5, -2, 1e-8)
 }
 
 func TestPerspectiveProjector(t *testing.T) {
 	per := NewPerspective(2)
 	assert.Equal(t, 0.4, per.NearZClip())
+	_, ok := per.(FarClipper)
+	assert.False(t, ok)
+	assert.True(t, per.(PerspectiveProjector).Perspective())
 
 	v := NewVec3(16, 30, -8)
 	p := BlankProjection()
 	assert.Same(t, p, per.Project(p, v))
 	assertProjectionEqual(t, 4, 7.5, 8, p, 1e-8)
+	verifyUnproject(t, per, 16, 30, -8, 1e-8)
+}
+
+func TestFOVPerspectiveProjector(t *testing.T) {
+	per := NewFOVPerspective(math.Pi/2, 2, .1, 100)
+	assert.Equal(t, .1, per.NearZClip())
+	assert.Equal(t, 100.0, per.(FarClipper).FarZClip())
+	assert.True(t, per.(PerspectiveProjector).Perspective())
+
+	// The corner of the frustum at z-distance 4.
+	v := NewVec3(8, 4, -4)
+	p := BlankProjection()
+	assert.Same(t, p, per.Project(p, v))
+	assertProjectionEqual(t, 1, 1, 4, p, 1e-8)
+
+	assertProjectionEqual(t, -.25, .5, 8, per.Project(p, NewVec3(-4, 4, -8)), 1e-8)
+	verifyUnproject(t, per, -4, 4, -8, 1e-8)
+}
+
+func TestCheckedFOVPerspectiveProjector(t *testing.T) {
+	per, err := NewCheckedFOVPerspective(math.Pi/2, 2, .1, math.Inf(1))
+	assert.NoError(t, err)
+	assert.Equal(t, NewFOVPerspective(math.Pi/2, 2, .1, math.Inf(1)), per)
+
+	for _, c := range []struct {
+		fovY, aspect, near, far float64
+		err                     string
+	}{
+		{0, 2, .1, 100, "field of view must be in (0,π), got 0"},
+		{-1, 2, .1, 100, "field of view must be in (0,π), got -1"},
+		{math.Pi, 2, .1, 100, "field of view must be in (0,π)"},
+		{math.NaN(), 2, .1, 100, "field of view must be in (0,π), got NaN"},
+		{1, 0, .1, 100, "aspect ratio must be positive and finite, got 0"},
+		{1, math.Inf(1), .1, 100, "aspect ratio must be positive and finite, got +Inf"},
+		{1, 2, 0, 100, "clipping planes must satisfy 0<near<far, got near 0 and far 100"},
+		{1, 2, -1, 100, "clipping planes must satisfy 0<near<far, got near -1 and far 100"},
+		{1, 2, 100, 100, "clipping planes must satisfy 0<near<far, got near 100 and far 100"},
+		{1, 2, .1, math.NaN(), "clipping planes must satisfy 0<near<far, got near 0.1 and far NaN"},
+	} {
+		_, err := NewCheckedFOVPerspective(c.fovY, c.aspect, c.near, c.far)
+		assert.ErrorContains(t, err, c.err)
+	}
 }
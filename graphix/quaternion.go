@@ -0,0 +1,146 @@
+package graphix
+
+import "math"
+
+// Quaternion represents the quaternion q[0]+q[1]i+q[2]j+q[3]k.
+// A unit quaternion represents a 3D rotation, and as a Transform it rotates a vector accordingly.
+type Quaternion [4]float64
+
+var _ AffineTransform = (*Quaternion)(nil)
+
+func IdentityQuaternion() *Quaternion              { return &Quaternion{1, 0, 0, 0} }
+func NewQuaternion(w, x, y, z float64) *Quaternion { return &Quaternion{w, x, y, z} }
+func NewCopyQuaternion(q *Quaternion) *Quaternion  { return &Quaternion{q[0], q[1], q[2], q[3]} }
+
+// NewAxisAngleQuaternion returns the unit quaternion representing the rotation around axis n by theta.
+// Caller is responsible for passing in a normalized n.
+func NewAxisAngleQuaternion(n *Vec3, theta float64) *Quaternion {
+	s, c := math.Sincos(theta / 2)
+	return &Quaternion{c, n[0] * s, n[1] * s, n[2] * s}
+}
+
+// NewMat3Quaternion returns the unit quaternion representing the rotation matrix m.
+// Caller is responsible for passing in an orthonormal m with determinant 1.
+func NewMat3Quaternion(m *Mat3) *Quaternion {
+	// See https://en.wikipedia.org/wiki/Rotation_matrix#Quaternion
+	// Pick the largest diagonal term to keep the square root well conditioned.
+	var q Quaternion
+	tr := m[0][0] + m[1][1] + m[2][2]
+	switch {
+	case tr > 0:
+		s := 2 * math.Sqrt(1+tr)
+		q = Quaternion{s / 4, (m[2][1] - m[1][2]) / s, (m[0][2] - m[2][0]) / s, (m[1][0] - m[0][1]) / s}
+	case m[0][0] > m[1][1] && m[0][0] > m[2][2]:
+		s := 2 * math.Sqrt(1+m[0][0]-m[1][1]-m[2][2])
+		q = Quaternion{(m[2][1] - m[1][2]) / s, s / 4, (m[0][1] + m[1][0]) / s, (m[0][2] + m[2][0]) / s}
+	case m[1][1] > m[2][2]:
+		s := 2 * math.Sqrt(1+m[1][1]-m[0][0]-m[2][2])
+		q = Quaternion{(m[0][2] - m[2][0]) / s, (m[0][1] + m[1][0]) / s, s / 4, (m[1][2] + m[2][1]) / s}
+	default:
+		s := 2 * math.Sqrt(1+m[2][2]-m[0][0]-m[1][1])
+		q = Quaternion{(m[1][0] - m[0][1]) / s, (m[0][2] + m[2][0]) / s, (m[1][2] + m[2][1]) / s, s / 4}
+	}
+	return q.Normalize(&q)
+}
+
+// Copy copies u into q and then returns q.
+func (q *Quaternion) Copy(u *Quaternion) *Quaternion {
+	if q != u {
+		*q = *u
+	}
+	return q
+}
+
+// Mul stores the Hamilton product a·b into q then returns q. As rotations, q applies b first and then a.
+func (q *Quaternion) Mul(a, b *Quaternion) *Quaternion {
+	q[0], q[1], q[2], q[3] = a[0]*b[0]-a[1]*b[1]-a[2]*b[2]-a[3]*b[3],
+		a[0]*b[1]+a[1]*b[0]+a[2]*b[3]-a[3]*b[2],
+		a[0]*b[2]-a[1]*b[3]+a[2]*b[0]+a[3]*b[1],
+		a[0]*b[3]+a[1]*b[2]-a[2]*b[1]+a[3]*b[0]
+	return q
+}
+
+// Conjugate stores the conjugate of u into q then returns q. For a unit quaternion this is the inverse rotation.
+func (q *Quaternion) Conjugate(u *Quaternion) *Quaternion {
+	q[0], q[1], q[2], q[3] = u[0], -u[1], -u[2], -u[3]
+	return q
+}
+
+// Dot returns the 4D dot product of q and u.
+func (q *Quaternion) Dot(u *Quaternion) float64 {
+	return q[0]*u[0] + q[1]*u[1] + q[2]*u[2] + q[3]*u[3]
+}
+
+// Norm returns the L2-norm of q.
+func (q *Quaternion) Norm() float64 { return math.Sqrt(q.Dot(q)) }
+
+// Normalize stores in q the normalized u and returns q.
+func (q *Quaternion) Normalize(u *Quaternion) *Quaternion {
+	s := 1 / u.Norm()
+	q[0], q[1], q[2], q[3] = u[0]*s, u[1]*s, u[2]*s, u[3]*s
+	return q
+}
+
+// Slerp stores into q the spherical linear interpolation between unit quaternions a and b at t∈[0,1] then returns q.
+// The interpolation always follows the shorter arc between the two rotations.
+func (q *Quaternion) Slerp(a, b *Quaternion, t float64) *Quaternion {
+	// See https://en.wikipedia.org/wiki/Slerp#Quaternion_Slerp
+	bb := *b
+	d := a.Dot(&bb)
+	// b and -b represent the same rotation, take the one closer to a.
+	if d < 0 {
+		bb[0], bb[1], bb[2], bb[3] = -bb[0], -bb[1], -bb[2], -bb[3]
+		d = -d
+	}
+	var sa, sb float64
+	if d > 1-1e-9 {
+		// Nearly identical rotations, fall back to linear interpolation to avoid dividing by sin(0).
+		sa, sb = 1-t, t
+	} else {
+		theta := math.Acos(d)
+		st := math.Sin(theta)
+		sa, sb = math.Sin((1-t)*theta)/st, math.Sin(t*theta)/st
+	}
+	q[0], q[1], q[2], q[3] = sa*a[0]+sb*bb[0], sa*a[1]+sb*bb[1], sa*a[2]+sb*bb[2], sa*a[3]+sb*bb[3]
+	return q.Normalize(q)
+}
+
+// AxisAngle returns the unit axis and the angle in [0,π] of the rotation represented by the unit quaternion q.
+// The axis is arbitrarily chosen as +x for the identity rotation.
+func (q *Quaternion) AxisAngle() (*Vec3, float64) {
+	w, x, y, z := q[0], q[1], q[2], q[3]
+	if w < 0 {
+		w, x, y, z = -w, -x, -y, -z
+	}
+	s := math.Sqrt(x*x + y*y + z*z)
+	if s == 0 {
+		return NewVec3(1, 0, 0), 0
+	}
+	return NewVec3(x/s, y/s, z/s), 2 * math.Atan2(s, w)
+}
+
+// Mat3 returns the rotation matrix of the unit quaternion q.
+func (q *Quaternion) Mat3() *Mat3 {
+	w, x, y, z := q[0], q[1], q[2], q[3]
+	return &Mat3{
+		{1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w)},
+		{2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w)},
+		{2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y)},
+	}
+}
+
+// Mat4 returns the homogeneous rotation matrix of the unit quaternion q.
+func (q *Quaternion) Mat4() *Mat4 { return q.Mat3().Mat4() }
+
+// Apply rotates u by the unit quaternion q and stores the result into v then returns v.
+func (q *Quaternion) Apply(v, u *Vec3) *Vec3 {
+	// v = u + 2w(r×u) + 2r×(r×u), where r is the vector part of q.
+	r := Vec3{q[1], q[2], q[3]}
+	var c1, c2 Vec3
+	c1.Cross(&r, u)
+	c2.Cross(&r, &c1)
+	v[0] = u[0] + 2*(q[0]*c1[0]+c2[0])
+	v[1] = u[1] + 2*(q[0]*c1[1]+c2[1])
+	v[2] = u[2] + 2*(q[0]*c1[2]+c2[2])
+	return v
+}
@@ -0,0 +1,106 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertQuaternionEqual(t *testing.T, exp, q *Quaternion, delta float64) {
+	for i := range 4 {
+		assert.InDelta(t, exp[i], q[i], delta)
+	}
+}
+
+func TestQuaternionApply(t *testing.T) {
+	q := NewAxisAngleQuaternion(NewVec3(0, 1, 0), math.Pi/4)
+	verifyTransform(t, q, 0, 1, 0, 0, 1, 0, 1e-8)
+	verifyTransform(t, q, 1/math.Sqrt(2), 0, 1/math.Sqrt(2), 0, 0, 1, 1e-8)
+	verifyTransform(t, q, 1/math.Sqrt(2), 0, -1/math.Sqrt(2), 1, 0, 0, 1e-8)
+
+	// Agrees with the axis-angle rotation.
+	n := NewVec3(1, 2, 3)
+	n.Normalize(n)
+	aar := NewAxisAngleRotation(n, 2.5)
+	q = NewAxisAngleQuaternion(n, 2.5)
+	u := NewVec3(-3, 5, 7)
+	v := aar.Apply(BlankVec3(), u)
+	verifyTransform(t, q, v[0], v[1], v[2], u[0], u[1], u[2], 1e-8)
+	verifyTransform(t, q.Mat4(), v[0], v[1], v[2], u[0], u[1], u[2], 1e-8)
+}
+
+func TestQuaternionMul(t *testing.T) {
+	a := NewAxisAngleQuaternion(NewVec3(0, 0, 1), math.Pi/2)
+	b := NewAxisAngleQuaternion(NewVec3(1, 0, 0), math.Pi/2)
+	q := &Quaternion{}
+	assert.Same(t, q, q.Mul(a, b))
+	// Rotate around x first, then around z.
+	verifyTransform(t, q, 0, 0, 1, 0, 1, 0, 1e-8)
+
+	// In place op.
+	assert.Same(t, a, a.Mul(a, b))
+	assertQuaternionEqual(t, q, a, 1e-12)
+
+	// Conjugate is the inverse rotation.
+	c := NewCopyQuaternion(q)
+	c.Mul(c.Conjugate(c), q)
+	assertQuaternionEqual(t, IdentityQuaternion(), c, 1e-12)
+}
+
+func TestQuaternionNormalize(t *testing.T) {
+	q := NewQuaternion(1, 1, 1, 1)
+	assert.InDelta(t, 2, q.Norm(), 1e-12)
+	assert.Same(t, q, q.Normalize(q))
+	assertQuaternionEqual(t, NewQuaternion(.5, .5, .5, .5), q, 1e-12)
+}
+
+func TestQuaternionAxisAngle(t *testing.T) {
+	n := NewVec3(1, -2, 2)
+	n.Normalize(n)
+	axis, theta := NewAxisAngleQuaternion(n, 1.2).AxisAngle()
+	assertVec3Equal(t, n[0], n[1], n[2], axis, 1e-8)
+	assert.InDelta(t, 1.2, theta, 1e-8)
+
+	// Angles beyond π come back as the opposite axis.
+	axis, theta = NewAxisAngleQuaternion(n, 2*math.Pi-1.2).AxisAngle()
+	assertVec3Equal(t, -n[0], -n[1], -n[2], axis, 1e-8)
+	assert.InDelta(t, 1.2, theta, 1e-8)
+
+	axis, theta = IdentityQuaternion().AxisAngle()
+	assertVec3Equal(t, 1, 0, 0, axis, 0)
+	assert.Equal(t, 0.0, theta)
+}
+
+func TestQuaternionMat3RoundTrip(t *testing.T) {
+	for _, theta := range []float64{0, .3, math.Pi / 2, 3, math.Pi} {
+		for _, n := range []*Vec3{NewVec3(1, 0, 0), NewVec3(0, 1, 0), NewVec3(0, 0, 1), NewVec3(1, 2, 3)} {
+			n.Normalize(n)
+			q := NewAxisAngleQuaternion(n, theta)
+			r := NewMat3Quaternion(q.Mat3())
+			// q and -q represent the same rotation.
+			if r.Dot(q) < 0 {
+				r = NewQuaternion(-r[0], -r[1], -r[2], -r[3])
+			}
+			assertQuaternionEqual(t, q, r, 1e-8)
+		}
+	}
+}
+
+func TestQuaternionSlerp(t *testing.T) {
+	z := NewVec3(0, 0, 1)
+	a := NewAxisAngleQuaternion(z, .2)
+	b := NewAxisAngleQuaternion(z, 1.4)
+	q := &Quaternion{}
+	assert.Same(t, q, q.Slerp(a, b, .25))
+	assertQuaternionEqual(t, NewAxisAngleQuaternion(z, .5), q, 1e-8)
+	assertQuaternionEqual(t, a, q.Slerp(a, b, 0), 1e-8)
+	assertQuaternionEqual(t, b, q.Slerp(a, b, 1), 1e-8)
+
+	// Takes the shorter arc: -b is the same rotation as b.
+	nb := NewQuaternion(-b[0], -b[1], -b[2], -b[3])
+	assertQuaternionEqual(t, NewAxisAngleQuaternion(z, .5), q.Slerp(a, nb, .25), 1e-8)
+
+	// Nearly identical rotations.
+	assertQuaternionEqual(t, a, q.Slerp(a, a, .7), 1e-8)
+}
@@ -35,10 +35,24 @@ func RandColor() color.NRGBA64 {
 	return hueRGB[rand.Intn(len(hueRGB))]
 }
 
+// RandColorFrom is like RandColor, but draws the hue from rng so that the color is reproducible.
+func RandColorFrom(rng *rand.Rand) color.NRGBA64 {
+	return hueRGB[rng.Intn(len(hueRGB))]
+}
+
 // RandColors returns n distinct full-saturation colors of random hues where adjacent colors have maximal distance along the hue circle.
 func RandColors(n int) []color.NRGBA64 {
+	return hueColors(n, rand.Float64())
+}
+
+// RandColorsFrom is like RandColors, but draws the hues from rng so that the colors are reproducible.
+func RandColorsFrom(rng *rand.Rand, n int) []color.NRGBA64 {
+	return hueColors(n, rng.Float64())
+}
+
+// Returns n hues starting from offset, see RandColors.
+func hueColors(n int, offset float64) []color.NRGBA64 {
 	colors := make([]color.NRGBA64, n)
-	offset := rand.Float64()
 
 	// Build order array based on parity.
 	order := make([]int, n)
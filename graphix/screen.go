@@ -1,10 +1,19 @@
 package graphix
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
 type Screen struct {
 	width  int
 	height int
 	x0     float64
 	y0     float64
+	x1     float64
+	y1     float64
 	xscale float64
 	yscale float64
 }
@@ -16,11 +25,41 @@ func NewScreen(width, height int, x0, y0, x1, y1 float64) *Screen {
 		height: height,
 		x0:     x0,
 		y0:     y0,
+		x1:     x1,
+		y1:     y1,
 		xscale: float64(width) / (x1 - x0),
 		yscale: float64(height) / (y1 - y0),
 	}
 }
 
+// NewCheckedScreen is like NewScreen, but returns an error if the dimension is not positive, or if the rectangle
+// is not finite or empty.
+func NewCheckedScreen(width, height int, x0, y0, x1, y1 float64) (*Screen, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("screen dimension must be positive, got %vx%v", width, height)
+	}
+	for _, v := range []float64{x0, y0, x1, y1} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("screen rectangle must be finite")
+		}
+	}
+	if x0 == x1 || y0 == y1 {
+		return nil, errors.New("screen rectangle must not be empty")
+	}
+	return NewScreen(width, height, x0, y0, x1, y1), nil
+}
+
+// NewFOVScreen creates a Screen with dimension width and height, mapping into the rectangle [-1,1)×(-1,1],
+// which is the projection of the field of view by NewFOVPerspective, NewFisheye and NewEquirectangular.
+func NewFOVScreen(width, height int) *Screen {
+	return NewScreen(width, height, -1, -1, 1, 1)
+}
+
+// NewScaledScreen creates a Screen with n times the width and height of sc, mapping into the same rectangle.
+func NewScaledScreen(sc *Screen, n int) *Screen {
+	return NewScreen(sc.width*n, sc.height*n, sc.x0, sc.y0, sc.x1, sc.y1)
+}
+
 // Map maps a projection q (world coordinate: right for +x, up for +y) into
 // p (screen coordinate: right for +x, down for +y) and returns p.
 func (sc *Screen) Map(p *Projection, q *Projection) *Projection {
@@ -29,5 +68,45 @@ func (sc *Screen) Map(p *Projection, q *Projection) *Projection {
 	return p
 }
 
+// Unmap is the inverse of Map. It maps a screen coordinate p back into the projection q and returns q.
+func (sc *Screen) Unmap(q *Projection, p *Projection) *Projection {
+	q[0] = p[0]/sc.xscale + sc.x0
+	q[1] = (float64(sc.height)-p[1])/sc.yscale + sc.y0
+	return q
+}
+
 func (sc *Screen) Width() int  { return sc.width }
 func (sc *Screen) Height() int { return sc.height }
+
+type screenJSON struct {
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	X0     float64 `json:"x0"`
+	Y0     float64 `json:"y0"`
+	X1     float64 `json:"x1"`
+	Y1     float64 `json:"y1"`
+}
+
+func (sc *Screen) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&screenJSON{
+		Width:  sc.width,
+		Height: sc.height,
+		X0:     sc.x0,
+		Y0:     sc.y0,
+		X1:     sc.x1,
+		Y1:     sc.y1,
+	})
+}
+
+func (sc *Screen) UnmarshalJSON(data []byte) error {
+	var sj screenJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return err
+	}
+	s, err := NewCheckedScreen(sj.Width, sj.Height, sj.X0, sj.Y0, sj.X1, sj.Y1)
+	if err != nil {
+		return err
+	}
+	*sc = *s
+	return nil
+}
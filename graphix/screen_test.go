@@ -1,6 +1,7 @@
 package graphix
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,4 +27,38 @@ func TestScreen(t *testing.T) {
 	// In place op.
 	assert.Same(t, q, sc.Map(q, q))
 	assertProjectionEqual(t, 50, 100, 0, q, 1e-8)
+
+	// Unmap is the inverse of Map.
+	assert.Same(t, p, sc.Unmap(p, q))
+	assertProjectionEqual(t, .2, .85, 0, p, 1e-8)
+	assert.Same(t, q, sc.Unmap(q, q))
+	assertProjectionEqual(t, .2, .85, 0, q, 1e-8)
+}
+
+func TestFOVScreen(t *testing.T) {
+	sc := NewFOVScreen(200, 100)
+	assert.Equal(t, 200, sc.Width())
+	assert.Equal(t, 100, sc.Height())
+	assertProjectionEqual(t, 0, 100, 0, sc.Map(BlankProjection(), NewProjection(-1, -1, 0)), 1e-8)
+	assertProjectionEqual(t, 150, 25, 0, sc.Map(BlankProjection(), NewProjection(.5, .5, 0)), 1e-8)
+}
+
+func TestScaledScreen(t *testing.T) {
+	sc := NewScaledScreen(NewScreen(200, 400, 0.1, 0.7, 0.5, 0.9), 3)
+	assert.Equal(t, 600, sc.Width())
+	assert.Equal(t, 1200, sc.Height())
+	assertProjectionEqual(t, 150, 300, 0, sc.Map(BlankProjection(), NewProjection(.2, .85, 0)), 1e-8)
+	assertProjectionEqual(t, .2, .85, 0, sc.Unmap(BlankProjection(), NewProjection(150, 300, 0)), 1e-8)
+}
+
+func TestCheckedScreen(t *testing.T) {
+	sc, err := NewCheckedScreen(200, 400, 0.1, 0.7, 0.5, 0.9)
+	assert.NoError(t, err)
+	assert.Equal(t, NewScreen(200, 400, 0.1, 0.7, 0.5, 0.9), sc)
+	_, err = NewCheckedScreen(200, -1, 0.1, 0.7, 0.5, 0.9)
+	assert.ErrorContains(t, err, "screen dimension must be positive, got 200x-1")
+	_, err = NewCheckedScreen(200, 400, 0.1, math.NaN(), 0.5, 0.9)
+	assert.ErrorContains(t, err, "screen rectangle must be finite")
+	_, err = NewCheckedScreen(200, 400, 0.1, 0.7, 0.1, 0.9)
+	assert.ErrorContains(t, err, "screen rectangle must not be empty")
 }
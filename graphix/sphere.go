@@ -0,0 +1,61 @@
+package graphix
+
+import "math"
+
+// Sphere represents a solid ball with Center and Radius.
+type Sphere struct {
+	Center *Vec3
+	Radius float64
+}
+
+var _ Volume = (*Sphere)(nil)
+
+// NewSphere creates a Sphere centered at c with radius r.
+func NewSphere(c *Vec3, r float64) *Sphere {
+	return &Sphere{Center: NewCopyVec3(c), Radius: r}
+}
+
+func (sp *Sphere) Contains(p *Vec3) bool {
+	var d Vec3
+	d.Sub(p, sp.Center)
+	return d.Dot(&d) <= sp.Radius*sp.Radius
+}
+
+func (sp *Sphere) SignedDistance(p *Vec3) float64 {
+	var d Vec3
+	return d.Sub(p, sp.Center).Norm() - sp.Radius
+}
+
+// ClosestPoint stores the point on the sphere closest to p into v then returns v.
+// If p is at the center, the point along +x is returned.
+func (sp *Sphere) ClosestPoint(v, p *Vec3) *Vec3 {
+	var d Vec3
+	d.Sub(p, sp.Center)
+	n := d.Norm()
+	if n == 0 {
+		d[0], n = 1, 1
+	}
+	return v.Add(sp.Center, d.Scale(&d, sp.Radius/n))
+}
+
+func (sp *Sphere) IntersectRay(r *Ray) (float64, bool) {
+	// Solve |o+t*d-c|²=R² for t.
+	var oc Vec3
+	oc.Sub(r.Origin, sp.Center)
+	a := r.Dir.Dot(r.Dir)
+	b := oc.Dot(r.Dir)
+	c := oc.Dot(&oc) - sp.Radius*sp.Radius
+	disc := b*b - a*c
+	if a == 0 || disc < 0 {
+		return 0, false
+	}
+	sq := math.Sqrt(disc)
+	if t := (-b - sq) / a; t >= 0 {
+		return t, true
+	}
+	// Origin inside the sphere, the ray exits at the far root.
+	if t := (-b + sq) / a; t >= 0 {
+		return t, true
+	}
+	return 0, false
+}
@@ -0,0 +1,42 @@
+package graphix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSphere(t *testing.T) {
+	sp := NewSphere(NewVec3(1, 0, 0), 2)
+	assert.True(t, sp.Contains(NewVec3(2, 1, 0)))
+	assert.True(t, sp.Contains(NewVec3(3, 0, 0)))
+	assert.False(t, sp.Contains(NewVec3(3, 1, 0)))
+	assert.InDelta(t, 3, sp.SignedDistance(NewVec3(1, 5, 0)), 1e-8)
+	assert.InDelta(t, -2, sp.SignedDistance(NewVec3(1, 0, 0)), 1e-8)
+
+	v := BlankVec3()
+	assert.Same(t, v, sp.ClosestPoint(v, NewVec3(1, 5, 0)))
+	assertVec3Equal(t, 1, 2, 0, v, 1e-8)
+	// Degenerate: p at center.
+	assertVec3Equal(t, 3, 0, 0, sp.ClosestPoint(v, NewVec3(1, 0, 0)), 1e-8)
+}
+
+func TestSphereIntersectRay(t *testing.T) {
+	sp := NewSphere(NewVec3(0, 0, 0), 1)
+	tt, ok := sp.IntersectRay(NewRay(NewVec3(-5, 0, 0), NewVec3(2, 0, 0)))
+	assert.True(t, ok)
+	assert.InDelta(t, 2, tt, 1e-8)
+
+	// Origin inside.
+	tt, ok = sp.IntersectRay(NewRay(NewVec3(0, 0, 0), NewVec3(0, 1, 0)))
+	assert.True(t, ok)
+	assert.InDelta(t, 1, tt, 1e-8)
+
+	// Miss.
+	_, ok = sp.IntersectRay(NewRay(NewVec3(-5, 2, 0), NewVec3(1, 0, 0)))
+	assert.False(t, ok)
+
+	// Behind.
+	_, ok = sp.IntersectRay(NewRay(NewVec3(5, 0, 0), NewVec3(1, 0, 0)))
+	assert.False(t, ok)
+}
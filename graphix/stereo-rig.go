@@ -0,0 +1,62 @@
+package graphix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// StereoRig represents a pair of cameras for the left and right eyes derived from a single center camera.
+type StereoRig struct {
+	left  *Camera
+	right *Camera
+}
+
+// NewStereoRig creates a StereoRig from the center camera cam. The eyes are displaced from the center by
+// interocular/2 along the camera's left and right directions respectively, sharing cam's projector and screen.
+// Instead of toeing the eyes in, the eyes' view transforms are sheared (i.e., asymmetric frustums) so that points
+// at z-distance convergence in front of the center camera have zero parallax, which avoids vertical parallax.
+// A convergence of +Inf makes parallel eyes.
+func NewStereoRig(cam *Camera, interocular, convergence float64) *StereoRig {
+	eye := func(e float64) *Camera {
+		// Moving the eye by -e along x moves points by +e, and points at z=-convergence back by -e.
+		shear := IdentityMat4()
+		shear[0][3] = e
+		if !math.IsInf(convergence, 1) {
+			shear[0][2] = e / convergence
+		}
+		return NewCamera(composeTransforms(cam.ViewTransform(), shear), cam.Projector(), cam.Screen())
+	}
+	return &StereoRig{
+		left:  eye(interocular / 2),
+		right: eye(-interocular / 2),
+	}
+}
+
+// NewCheckedStereoRig is like NewStereoRig, but returns an error if cam is nil, if interocular is negative or not
+// finite, or if convergence is not positive.
+func NewCheckedStereoRig(cam *Camera, interocular, convergence float64) (*StereoRig, error) {
+	if cam == nil {
+		return nil, errors.New("camera must not be nil")
+	}
+	if !(interocular >= 0 && !math.IsInf(interocular, 1)) {
+		return nil, fmt.Errorf("interocular distance must be non-negative and finite, got %v", interocular)
+	}
+	if !(convergence > 0) {
+		return nil, fmt.Errorf("convergence must be positive, got %v", convergence)
+	}
+	return NewStereoRig(cam, interocular, convergence), nil
+}
+
+func (rig *StereoRig) Left() *Camera  { return rig.left }
+func (rig *StereoRig) Right() *Camera { return rig.right }
+
+// Returns the transform applying a then b, which is a matrix if a is affine.
+func composeTransforms(a Transform, b AffineTransform) Transform {
+	if aa, ok := a.(AffineTransform); ok {
+		return Compose(aa, b)
+	}
+	return TransformFunc(func(v, u *Vec3) *Vec3 {
+		return b.Apply(v, a.Apply(v, u))
+	})
+}
@@ -0,0 +1,70 @@
+package graphix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStereoRig(t *testing.T) {
+	pr := NewPerspective(1)
+	sc := NewScreen(100, 100, -1, -1, 1, 1)
+	cam := NewCamera(NewViewTransform(NewVec3(0, 0, 10), NewVec3(0, 0, -1), NewVec3(0, 1, 0)), pr, sc)
+	rig := NewStereoRig(cam, .2, 5)
+	assert.Same(t, pr, rig.Left().Projector())
+	assert.Same(t, sc, rig.Right().Screen())
+
+	project := func(c *Camera, v *Vec3) *Projection {
+		return c.Projector().Project(BlankProjection(), c.ViewTransform().Apply(BlankVec3(), v))
+	}
+	// Zero parallax at the convergence distance.
+	v := NewVec3(.3, .4, 5)
+	l, r := project(rig.Left(), v), project(rig.Right(), v)
+	assertProjectionEqual(t, r[0], r[1], r[2], l, 1e-8)
+	c := project(cam, v)
+	assertProjectionEqual(t, c[0], c[1], c[2], l, 1e-8)
+
+	// Points beyond convergence appear further right in the right eye, points in front further left.
+	v = NewVec3(.3, .4, 0)
+	l, r = project(rig.Left(), v), project(rig.Right(), v)
+	assert.Greater(t, r[0], l[0])
+	assert.InDelta(t, l[1], r[1], 1e-8)
+	v = NewVec3(.3, .4, 8)
+	l, r = project(rig.Left(), v), project(rig.Right(), v)
+	assert.Less(t, r[0], l[0])
+	assert.InDelta(t, l[1], r[1], 1e-8)
+
+	// The left eye sits to the left of the center.
+	assertVec3Equal(t, 0, 0, 0, rig.Left().ViewTransform().Apply(BlankVec3(), NewVec3(-.1, 0, 10)), 1e-8)
+	assertVec3Equal(t, 0, 0, 0, rig.Right().ViewTransform().Apply(BlankVec3(), NewVec3(.1, 0, 10)), 1e-8)
+}
+
+func TestStereoRigParallel(t *testing.T) {
+	cam := NewOrtho2DCamera(&Screen{})
+	// Non-affine view transform.
+	cam = NewCamera(TransformFunc(cam.ViewTransform().Apply), cam.Projector(), cam.Screen())
+	rig := NewStereoRig(cam, 1, math.Inf(1))
+	assertVec3Equal(t, 1.5, 2, 2, rig.Left().ViewTransform().Apply(BlankVec3(), NewVec3(1, 2, 3)), 1e-8)
+	assertVec3Equal(t, .5, 2, 2, rig.Right().ViewTransform().Apply(BlankVec3(), NewVec3(1, 2, 3)), 1e-8)
+}
+
+func TestCheckedStereoRig(t *testing.T) {
+	cam := NewOrtho2DCamera(NewScreen(100, 100, -1, -1, 1, 1))
+	rig, err := NewCheckedStereoRig(cam, .2, math.Inf(1))
+	assert.NoError(t, err)
+	assert.Equal(t, NewStereoRig(cam, .2, math.Inf(1)), rig)
+	_, err = NewCheckedStereoRig(cam, 0, 5)
+	assert.NoError(t, err)
+
+	_, err = NewCheckedStereoRig(nil, .2, 5)
+	assert.ErrorContains(t, err, "camera must not be nil")
+	for _, interocular := range []float64{-.2, math.Inf(1), math.NaN()} {
+		_, err = NewCheckedStereoRig(cam, interocular, 5)
+		assert.ErrorContains(t, err, "interocular distance must be non-negative and finite")
+	}
+	for _, convergence := range []float64{0, -5, math.Inf(-1), math.NaN()} {
+		_, err = NewCheckedStereoRig(cam, .2, convergence)
+		assert.ErrorContains(t, err, "convergence must be positive")
+	}
+}
@@ -0,0 +1,80 @@
+package graphix
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextSettings defines how annotation text is drawn.
+type TextSettings struct {
+	// Font of the text, nil means the Go regular font.
+	Font *truetype.Font
+	// Font size in pixels, zero means 12.
+	Size float64
+	// Color of the text, nil means white.
+	Color color.Color
+}
+
+var goRegularFont = sync.OnceValue(func() *truetype.Font {
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	return f
+})
+
+// Resolves the defaults of ts.
+func (ts *TextSettings) resolve() TextSettings {
+	r := *ts
+	if r.Font == nil {
+		r.Font = goRegularFont()
+	}
+	if r.Size == 0 {
+		r.Size = 12
+	}
+	if r.Color == nil {
+		r.Color = color.White
+	}
+	return r
+}
+
+// Draws text with the font settings, whose size is in pixels.
+type textDrawer struct {
+	size    float64
+	face    font.Face
+	ascent  int
+	descent int
+	color   color.Color
+}
+
+func newTextDrawer(ts *TextSettings) *textDrawer {
+	r := ts.resolve()
+	// At 72 DPI, a point is a pixel.
+	face := truetype.NewFace(r.Font, &truetype.Options{Size: r.Size, Hinting: font.HintingFull})
+	m := face.Metrics()
+	return &textDrawer{size: r.Size, face: face, ascent: m.Ascent.Ceil(), descent: m.Descent.Ceil(), color: r.Color}
+}
+
+// Returns the advance width of s in pixels.
+func (td *textDrawer) width(s string) int { return font.MeasureString(td.face, s).Ceil() }
+
+// Draws s on dst with the top-left corner of its line box at (x, y).
+func (td *textDrawer) draw(dst draw.Image, s string, x, y int) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(td.color),
+		Face: td.face,
+		Dot:  fixed.P(x, y+td.ascent),
+	}
+	d.DrawString(s)
+}
+
+// Returns the height of a line box in pixels, which contains the ascenders and descenders.
+func (td *textDrawer) lineHeight() int { return td.ascent + td.descent }
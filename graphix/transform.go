@@ -13,9 +13,12 @@ var _ Transform = (TransformFunc)(nil)
 
 func (tf TransformFunc) Apply(v, u *Vec3) *Vec3 { return tf(v, u) }
 
+type identityTransform struct{}
+
+var _ AffineTransform = identityTransform{}
+
 // IdentityTransform returns an identity transform of a vector.
-func IdentityTransform() Transform {
-	return TransformFunc(func(v, u *Vec3) *Vec3 {
-		return v.Copy(u)
-	})
-}
+func IdentityTransform() Transform { return identityTransform{} }
+
+func (identityTransform) Apply(v, u *Vec3) *Vec3 { return v.Copy(u) }
+func (identityTransform) Mat4() *Mat4            { return IdentityMat4() }
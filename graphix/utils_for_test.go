@@ -25,3 +25,10 @@ func verifyTransform(t *testing.T, tr Transform, expTo0, expTo1, expTo2, from0,
 	assert.Equal(t, u, tr.Apply(u, u))
 	assertVec3Equal(t, expTo0, expTo1, expTo2, u, delta)
 }
+
+func verifyUnproject(t *testing.T, pr Projector, v0, v1, v2, delta float64) {
+	p := pr.Project(BlankProjection(), NewVec3(v0, v1, v2))
+	v := BlankVec3()
+	assert.Same(t, v, pr.(Unprojector).Unproject(v, p))
+	assertVec3Equal(t, v0, v1, v2, v, delta)
+}
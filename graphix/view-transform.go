@@ -1,5 +1,14 @@
 package graphix
 
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Tolerance used when validating that vectors are normalized and mutually orthogonal.
+const orthonormalTolerance = 1e-6
+
 // ViewTransform is defined with 3 parameters all of which are in world frame:
 // - pos defines the coordinates of the camera's position;
 // - forward defines the unit forward direction along which the camera is facing;
@@ -14,11 +23,11 @@ type viewTransform struct {
 	uz *Vec3
 }
 
-var _ Transform = (*viewTransform)(nil)
+var _ AffineTransform = (*viewTransform)(nil)
 
 // NewViewTransform creates a ViewTransform with camera position at pos, looking into the forward direction and pointing up to the up direction.
 // It is the caller's responsibility to ensure that forward and up are mutually orthogonal and normalized.
-func NewViewTransform(pos, forward, up *Vec3) Transform {
+func NewViewTransform(pos, forward, up *Vec3) AffineTransform {
 	return &viewTransform{
 		pos: NewCopyVec3(pos),
 		ux:  BlankVec3().Cross(forward, up),
@@ -27,8 +36,99 @@ func NewViewTransform(pos, forward, up *Vec3) Transform {
 	}
 }
 
+// NewCheckedViewTransform is like NewViewTransform, but returns an error instead of a skewed transform
+// if forward and up are not normalized and mutually orthogonal.
+func NewCheckedViewTransform(pos, forward, up *Vec3) (AffineTransform, error) {
+	if err := checkFinite("pos", pos); err != nil {
+		return nil, err
+	}
+	if err := checkNormalized("forward", forward); err != nil {
+		return nil, err
+	}
+	if err := checkNormalized("up", up); err != nil {
+		return nil, err
+	}
+	if err := checkOrthogonal("forward", "up", forward, up); err != nil {
+		return nil, err
+	}
+	return NewViewTransform(pos, forward, up), nil
+}
+
+// LookAt creates a view transform with camera positioned at eye and facing target.
+// upHint needs to be neither normalized nor orthogonal to the viewing direction: the camera's up direction is
+// obtained by Gram-Schmidt orthonormalization of upHint against the viewing direction.
+// An error is returned if eye and target coincide or if upHint is zero or parallel to the viewing direction.
+func LookAt(eye, target, upHint *Vec3) (AffineTransform, error) {
+	if err := checkFinite("eye", eye); err != nil {
+		return nil, err
+	}
+	if err := checkFinite("target", target); err != nil {
+		return nil, err
+	}
+	if err := checkFinite("up hint", upHint); err != nil {
+		return nil, err
+	}
+	forward := BlankVec3().Sub(target, eye)
+	fn := forward.Norm()
+	if fn == 0 {
+		return nil, errors.New("eye and target must not coincide")
+	}
+	forward.Scale(forward, 1/fn)
+	un := upHint.Norm()
+	if un == 0 {
+		return nil, errors.New("up hint must not be a zero vector")
+	}
+	up := BlankVec3().Scale(forward, -upHint.Dot(forward))
+	up.Add(up, upHint)
+	n := up.Norm()
+	if n <= orthonormalTolerance*un {
+		return nil, errors.New("up hint must not be parallel to the viewing direction")
+	}
+	return NewViewTransform(eye, forward, up.Scale(up, 1/n)), nil
+}
+
+func checkFinite(name string, v *Vec3) error {
+	for _, c := range v {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return fmt.Errorf("%v must be finite, got %v", name, *v)
+		}
+	}
+	return nil
+}
+
+func checkNormalized(name string, v *Vec3) error {
+	if err := checkFinite(name, v); err != nil {
+		return err
+	}
+	if n := v.Norm(); math.Abs(n-1) > orthonormalTolerance {
+		return fmt.Errorf("%v must be normalized, got norm %v", name, n)
+	}
+	return nil
+}
+
+// checkOrthogonal checks the cosine of the angle between u and v, a zero vector is orthogonal to anything.
+func checkOrthogonal(name1, name2 string, u, v *Vec3) error {
+	nn := u.Norm() * v.Norm()
+	if nn == 0 {
+		return nil
+	}
+	if c := u.Dot(v) / nn; math.Abs(c) > orthonormalTolerance {
+		return fmt.Errorf("%v and %v must be orthogonal, got cosine %v", name1, name2, c)
+	}
+	return nil
+}
+
 func (vt *viewTransform) Apply(v, u *Vec3) *Vec3 {
 	v.Sub(u, vt.pos)
 	v[0], v[1], v[2] = v.Dot(vt.ux), v.Dot(vt.uy), v.Dot(vt.uz)
 	return v
 }
+
+func (vt *viewTransform) Mat4() *Mat4 {
+	return &Mat4{
+		{vt.ux[0], vt.ux[1], vt.ux[2], -vt.ux.Dot(vt.pos)},
+		{vt.uy[0], vt.uy[1], vt.uy[2], -vt.uy.Dot(vt.pos)},
+		{vt.uz[0], vt.uz[1], vt.uz[2], -vt.uz.Dot(vt.pos)},
+		{0, 0, 0, 1},
+	}
+}
@@ -3,6 +3,8 @@ package graphix
 import (
 	"math"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestViewTransform(t *testing.T) {
@@ -24,3 +26,42 @@ func TestViewTransform(t *testing.T) {
 	verifyTransform(t, vt, -1/math.Sqrt(2), -1/math.Sqrt(2), 0, 1, 0, 0, 1e-8)
 	verifyTransform(t, vt, 1/math.Sqrt(2), -1/math.Sqrt(2), 0, 0, 1, 0, 1e-8)
 }
+
+func TestViewTransformMat4(t *testing.T) {
+	vt := NewViewTransform(NewVec3(1, 1, 0), NewVec3(0, 0, 1), NewVec3(1/math.Sqrt(2), 1/math.Sqrt(2), 0))
+	m := vt.Mat4()
+	u := NewVec3(-3, 5, 7)
+	v := vt.Apply(BlankVec3(), u)
+	verifyTransform(t, m, v[0], v[1], v[2], u[0], u[1], u[2], 1e-8)
+}
+
+func TestCheckedViewTransform(t *testing.T) {
+	vt, err := NewCheckedViewTransform(NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(0, 1, 0))
+	assert.NoError(t, err)
+	verifyTransform(t, vt, -1, 0, -1, 1, 0, 0, 1e-8)
+
+	_, err = NewCheckedViewTransform(NewVec3(0, 0, -1), NewVec3(0, 0, 2), NewVec3(0, 1, 0))
+	assert.ErrorContains(t, err, "forward must be normalized")
+	_, err = NewCheckedViewTransform(NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(0, 0, 0))
+	assert.ErrorContains(t, err, "up must be normalized")
+	_, err = NewCheckedViewTransform(NewVec3(0, 0, -1), NewVec3(0, 0, 1), NewVec3(0, .6, .8))
+	assert.ErrorContains(t, err, "forward and up must be orthogonal")
+	_, err = NewCheckedViewTransform(NewVec3(math.NaN(), 0, -1), NewVec3(0, 0, 1), NewVec3(0, 1, 0))
+	assert.ErrorContains(t, err, "pos must be finite")
+}
+
+func TestLookAt(t *testing.T) {
+	// Same as the view from (0,1,0) into -y with +x as up, with a skewed and unnormalized up hint.
+	vt, err := LookAt(NewVec3(0, 1, 0), NewVec3(0, -3, 0), NewVec3(2, 5, 0))
+	assert.NoError(t, err)
+	verifyTransform(t, vt, 0, 0, -1, 0, 0, 0, 1e-8)
+	verifyTransform(t, vt, 0, 1, -1, 1, 0, 0, 1e-8)
+	verifyTransform(t, vt, 1, 0, -1, 0, 0, 1, 1e-8)
+
+	_, err = LookAt(NewVec3(1, 1, 1), NewVec3(1, 1, 1), NewVec3(0, 1, 0))
+	assert.ErrorContains(t, err, "eye and target must not coincide")
+	_, err = LookAt(NewVec3(0, 0, 1), NewVec3(0, 0, 0), NewVec3(0, 0, 0))
+	assert.ErrorContains(t, err, "up hint must not be a zero vector")
+	_, err = LookAt(NewVec3(0, 0, 1), NewVec3(0, 0, 0), NewVec3(0, 0, 3))
+	assert.ErrorContains(t, err, "up hint must not be parallel to the viewing direction")
+}
@@ -0,0 +1,39 @@
+package zraster
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// AlphaMode defines how the colors of the output image relate to its alpha channel, which only matters when the
+// background is not opaque.
+type AlphaMode int
+
+const (
+	// Colors are premultiplied by alpha, see OutputFormat for the type of the output image.
+	PremultipliedAlpha AlphaMode = iota
+	// Colors are not premultiplied by alpha, see OutputFormat for the type of the output image.
+	StraightAlpha
+)
+
+func (am AlphaMode) validate() error {
+	if am != PremultipliedAlpha && am != StraightAlpha {
+		return fmt.Errorf("unknown alpha mode %v", int(am))
+	}
+	return nil
+}
+
+// Creates the canvas to composite the fragments onto, filled with the background.
+func newBlankCanvas(settings *Settings) canvas {
+	cv := newCanvas(
+		settings.Format,
+		image.Rect(0, 0, settings.Camera.Screen().Width(), settings.Camera.Screen().Height()),
+	)
+	bg := settings.Background
+	if bg == nil {
+		bg = image.Black
+	}
+	draw.Draw(cv, cv.Bounds(), bg, bg.Bounds().Min, draw.Src)
+	return cv
+}
@@ -0,0 +1,216 @@
+package zraster
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/euphoricrhino/go-common/graphix"
+)
+
+// OutputFormat defines the precision in which the fragments are composited, and the type of the output image.
+type OutputFormat int
+
+const (
+	// 8 bits per channel, the output is an *image.RGBA, or an *image.NRGBA with StraightAlpha.
+	RGBA8 OutputFormat = iota
+	// 16 bits per channel, the output is an *image.RGBA64, or an *image.NRGBA64 with StraightAlpha.
+	// It can be encoded as a 16-bit PNG by png.Encode.
+	RGBA16
+	// float32 per channel, the output is a *graphix.FloatImage, which can be encoded by graphix.EncodePFM, or as
+	// a 16-bit PNG by png.Encode.
+	Float32
+)
+
+func (of OutputFormat) validate() error {
+	if of < RGBA8 || of > Float32 {
+		return fmt.Errorf("unknown output format %v", int(of))
+	}
+	return nil
+}
+
+// canvas is an image with premultiplied colors which the fragments are composited onto.
+// Different pixels can be accessed concurrently.
+type canvas interface {
+	draw.Image
+	// Composites the fragments sorted by z onto pixel (x,y).
+	composite(x, y int, sorted []zColor)
+	// Returns the channels of pixel (x,y) normalized to [0,1].
+	get(x, y int) [4]float64
+	// Sets the channels of pixel (x,y) normalized to [0,1].
+	set(x, y int, px [4]float64)
+	// Returns the output image according to the alpha mode. The canvas must not be used afterwards.
+	output(am AlphaMode) draw.Image
+}
+
+// Creates a transparent canvas of format with bounds r.
+func newCanvas(format OutputFormat, r image.Rectangle) canvas {
+	switch format {
+	case RGBA16:
+		return rgba16Canvas{image.NewRGBA64(r)}
+	case Float32:
+		return floatCanvas{graphix.NewFloatImage(r)}
+	default:
+		return rgba8Canvas{image.NewRGBA(r)}
+	}
+}
+
+// Returns the canvas backed by img, or nil if img is not of a canvas type.
+func asCanvas(img image.Image) canvas {
+	switch img := img.(type) {
+	case *image.RGBA:
+		return rgba8Canvas{img}
+	case *image.RGBA64:
+		return rgba16Canvas{img}
+	case *graphix.FloatImage:
+		if !img.Straight {
+			return floatCanvas{img}
+		}
+	}
+	return nil
+}
+
+// Returns the output format of cv.
+func formatOf(cv canvas) OutputFormat {
+	switch cv.(type) {
+	case rgba16Canvas:
+		return RGBA16
+	case floatCanvas:
+		return Float32
+	default:
+		return RGBA8
+	}
+}
+
+// Fragment channels are the 16-bit colors multiplied with the 16-bit span alpha.
+const fragmentMax = math.MaxUint16 * math.MaxUint16
+
+type rgba8Canvas struct{ *image.RGBA }
+
+func (cv rgba8Canvas) composite(x, y int, sorted []zColor) {
+	idx := cv.PixOffset(x, y)
+
+	// Paint the pixels from far to near, multiplying the alpha at each layer in order.
+	for _, zc := range sorted {
+		dr := uint32(cv.Pix[idx+0])
+		dg := uint32(cv.Pix[idx+1])
+		db := uint32(cv.Pix[idx+2])
+		da := uint32(cv.Pix[idx+3])
+		a := (math.MaxUint16 - (zc.a / math.MaxUint16)) * 257 // 65535/255=257
+		cv.Pix[idx+0] = uint8((dr*a + zc.r) / math.MaxUint16 >> 8)
+		cv.Pix[idx+1] = uint8((dg*a + zc.g) / math.MaxUint16 >> 8)
+		cv.Pix[idx+2] = uint8((db*a + zc.b) / math.MaxUint16 >> 8)
+		cv.Pix[idx+3] = uint8((da*a + zc.a) / math.MaxUint16 >> 8)
+	}
+}
+
+func (cv rgba8Canvas) get(x, y int) [4]float64 {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	return [4]float64{
+		float64(s[0]) / math.MaxUint8,
+		float64(s[1]) / math.MaxUint8,
+		float64(s[2]) / math.MaxUint8,
+		float64(s[3]) / math.MaxUint8,
+	}
+}
+
+func (cv rgba8Canvas) set(x, y int, px [4]float64) {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	for c, v := range px {
+		s[c] = uint8(math.Round(v * math.MaxUint8))
+	}
+}
+
+func (cv rgba8Canvas) output(am AlphaMode) draw.Image {
+	if am == PremultipliedAlpha {
+		return cv.RGBA
+	}
+	out := image.NewNRGBA(cv.Rect)
+	draw.Draw(out, out.Rect, cv.RGBA, cv.Rect.Min, draw.Src)
+	return out
+}
+
+type rgba16Canvas struct{ *image.RGBA64 }
+
+func (cv rgba16Canvas) composite(x, y int, sorted []zColor) {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	// Paint the pixels from far to near with rounding.
+	for _, zc := range sorted {
+		a := uint64(math.MaxUint16 - zc.a/math.MaxUint16)
+		for c, v := range [4]uint32{zc.r, zc.g, zc.b, zc.a} {
+			d := uint64(s[2*c])<<8 | uint64(s[2*c+1])
+			d = (d*a + uint64(v) + math.MaxUint16/2) / math.MaxUint16
+			s[2*c], s[2*c+1] = uint8(d>>8), uint8(d)
+		}
+	}
+}
+
+func (cv rgba16Canvas) get(x, y int) [4]float64 {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	var px [4]float64
+	for c := range px {
+		px[c] = float64(uint16(s[2*c])<<8|uint16(s[2*c+1])) / math.MaxUint16
+	}
+	return px
+}
+
+func (cv rgba16Canvas) set(x, y int, px [4]float64) {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	for c, v := range px {
+		d := uint16(math.Round(v * math.MaxUint16))
+		s[2*c], s[2*c+1] = uint8(d>>8), uint8(d)
+	}
+}
+
+func (cv rgba16Canvas) output(am AlphaMode) draw.Image {
+	if am == PremultipliedAlpha {
+		return cv.RGBA64
+	}
+	out := image.NewNRGBA64(cv.Rect)
+	draw.Draw(out, out.Rect, cv.RGBA64, cv.Rect.Min, draw.Src)
+	return out
+}
+
+type floatCanvas struct{ *graphix.FloatImage }
+
+func (cv floatCanvas) composite(x, y int, sorted []zColor) {
+	i := cv.PixOffset(x, y)
+	s := cv.Pix[i : i+4 : i+4]
+	// Paint the pixels from far to near.
+	for _, zc := range sorted {
+		a := 1 - float32(float64(zc.a)/fragmentMax)
+		s[0] = s[0]*a + float32(float64(zc.r)/fragmentMax)
+		s[1] = s[1]*a + float32(float64(zc.g)/fragmentMax)
+		s[2] = s[2]*a + float32(float64(zc.b)/fragmentMax)
+		s[3] = s[3]*a + float32(float64(zc.a)/fragmentMax)
+	}
+}
+
+func (cv floatCanvas) get(x, y int) [4]float64 {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	return [4]float64{float64(s[0]), float64(s[1]), float64(s[2]), float64(s[3])}
+}
+
+func (cv floatCanvas) set(x, y int, px [4]float64) {
+	s := cv.Pix[cv.PixOffset(x, y):]
+	for c, v := range px {
+		s[c] = float32(v)
+	}
+}
+
+func (cv floatCanvas) output(am AlphaMode) draw.Image {
+	if am == PremultipliedAlpha {
+		return cv.FloatImage
+	}
+	for i := 0; i < len(cv.Pix); i += 4 {
+		s := cv.Pix[i : i+4 : i+4]
+		if s[3] == 0 {
+			s[0], s[1], s[2] = 0, 0, 0
+			continue
+		}
+		s[0], s[1], s[2] = s[0]/s[3], s[1]/s[3], s[2]/s[3]
+	}
+	cv.Straight = true
+	return cv.FloatImage
+}
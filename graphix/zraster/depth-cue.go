@@ -0,0 +1,89 @@
+package zraster
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// FogMode defines how the fog factor grows with the distance between DepthCue.Near and DepthCue.Far.
+type FogMode int
+
+const (
+	// The fog factor grows linearly from 0 at Near to 1 at Far.
+	LinearFog FogMode = iota
+	// The fog factor is 1-exp(-Density·t), where t grows linearly from 0 at Near to 1 at Far.
+	ExponentialFog
+	// The fog factor is Func(t), where t grows linearly from 0 at Near to 1 at Far.
+	CustomFog
+)
+
+// DepthCue attenuates the fragments toward a fog color according to their z-distance from the camera.
+// A fragment with fog factor f has its color and alpha interpolated by f from their own values to the fog color.
+type DepthCue struct {
+	Mode FogMode
+	// Z-distances where the attenuation starts and ends. Fragments nearer than Near are not attenuated, and
+	// fragments farther than Far are attenuated as at Far.
+	Near, Far float64
+	// Density of ExponentialFog.
+	Density float64
+	// Fog factor in [0,1] of CustomFog, as a function of t∈[0,1].
+	Func func(t float64) float64
+	// Color of the fog, nil means transparent, which fades the fragments out.
+	Color color.Color
+}
+
+func (dc *DepthCue) validate() error {
+	if !(dc.Near < dc.Far) || math.IsInf(dc.Near, 0) || math.IsInf(dc.Far, 0) {
+		return fmt.Errorf("depth cue must have finite near < far, got near %v and far %v", dc.Near, dc.Far)
+	}
+	switch dc.Mode {
+	case LinearFog:
+	case ExponentialFog:
+		if !(dc.Density > 0) || math.IsInf(dc.Density, 1) {
+			return fmt.Errorf("exponential fog must have a positive density, got %v", dc.Density)
+		}
+	case CustomFog:
+		if dc.Func == nil {
+			return errors.New("custom fog must have a function")
+		}
+	default:
+		return fmt.Errorf("unknown fog mode %v", int(dc.Mode))
+	}
+	return nil
+}
+
+// Returns the fog factor at z-distance z.
+func (dc *DepthCue) factor(z float64) float64 {
+	t := min(max((z-dc.Near)/(dc.Far-dc.Near), 0), 1)
+	switch dc.Mode {
+	case ExponentialFog:
+		return 1 - math.Exp(-dc.Density*t)
+	case CustomFog:
+		return min(max(dc.Func(t), 0), 1)
+	default:
+		return t
+	}
+}
+
+// Attenuates the fragments according to their z-distance.
+func (dc *DepthCue) attenuate(frags []zColor) {
+	var fr, fg, fb, fa uint32
+	if dc.Color != nil {
+		fr, fg, fb, fa = dc.Color.RGBA()
+	}
+	for i := range frags {
+		zc := &frags[i]
+		f := dc.factor(float64(zc.z))
+		if f == 0 {
+			continue
+		}
+		// The fog color is weighted by the coverage of the fragment, i.e., the fragment alpha.
+		cov := float64(zc.a) / math.MaxUint16
+		lerp := func(c, fc uint32) uint32 {
+			return uint32(math.Round((1-f)*float64(c) + f*cov*float64(fc)))
+		}
+		zc.r, zc.g, zc.b, zc.a = lerp(zc.r, fr), lerp(zc.g, fg), lerp(zc.b, fb), lerp(zc.a, fa)
+	}
+}
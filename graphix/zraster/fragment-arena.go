@@ -0,0 +1,88 @@
+package zraster
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// fragment is a zColor recorded at a pixel.
+type fragment struct {
+	// Pixel index y*width+x.
+	pixel int32
+	zColor
+}
+
+// Arenas are reused across renders, since consecutive frames typically have similar numbers of fragments.
+var (
+	fragmentPool = sync.Pool{New: func() any { return new([]fragment) }}
+	zColorPool   = sync.Pool{New: func() any { return new([]zColor) }}
+	offsetPool   = sync.Pool{New: func() any { return new([]int32) }}
+)
+
+// Returns a slice of length n from pool, reallocating its backing array if too small.
+func getSlice[T any](pool *sync.Pool, n int) *[]T {
+	s := pool.Get().(*[]T)
+	if cap(*s) < n {
+		*s = make([]T, n)
+	}
+	*s = (*s)[:n]
+	return s
+}
+
+// fragmentsByPixel stores the fragments of all pixels contiguously, where the fragments of pixel i are
+// zcs[offsets[i]:offsets[i+1]].
+type fragmentsByPixel struct {
+	zcs     *[]zColor
+	offsets *[]int32
+}
+
+// Groups the fragments recorded by all workers by pixel with a counting sort. The fragments of each pixel keep the
+// order in which they are recorded by the workers, in the order of the workers.
+func groupByPixel(frags [][]fragment, pixels int) (*fragmentsByPixel, error) {
+	total := 0
+	for _, fs := range frags {
+		total += len(fs)
+	}
+	// The offsets are compact, but limit the number of fragments.
+	if total > math.MaxInt32 {
+		return nil, errors.New("too many fragments")
+	}
+	fbp := &fragmentsByPixel{
+		zcs:     getSlice[zColor](&zColorPool, total),
+		offsets: getSlice[int32](&offsetPool, pixels+1),
+	}
+	offsets, zcs := *fbp.offsets, *fbp.zcs
+	clear(offsets)
+	for _, fs := range frags {
+		for i := range fs {
+			offsets[fs[i].pixel]++
+		}
+	}
+	// Turn the counts into the end offset of each pixel.
+	for i := 1; i < pixels; i++ {
+		offsets[i] += offsets[i-1]
+	}
+	offsets[pixels] = int32(total)
+	// Scatter the fragments backwards, which turns the end offset of each pixel into its start offset.
+	for w := len(frags) - 1; w >= 0; w-- {
+		fs := frags[w]
+		for i := len(fs) - 1; i >= 0; i-- {
+			p := fs[i].pixel
+			offsets[p]--
+			zcs[offsets[p]] = fs[i].zColor
+		}
+	}
+	return fbp, nil
+}
+
+// Returns the fragments of pixel i.
+func (fbp *fragmentsByPixel) at(i int) []zColor {
+	return (*fbp.zcs)[(*fbp.offsets)[i]:(*fbp.offsets)[i+1]]
+}
+
+// Returns the storage into the pools.
+func (fbp *fragmentsByPixel) release() {
+	zColorPool.Put(fbp.zcs)
+	offsetPool.Put(fbp.offsets)
+}
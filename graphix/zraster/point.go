@@ -0,0 +1,160 @@
+package zraster
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/euphoricrhino/go-common/graphix"
+	"github.com/golang/freetype/raster"
+	"golang.org/x/image/math/fixed"
+)
+
+// PointShape defines how a SpacePoint is drawn.
+type PointShape int
+
+const (
+	// A filled disc facing the camera.
+	DiscPoint PointShape = iota
+	// A disc shaded as a sphere lit from the upper left of the camera, whose z-distance follows the front surface
+	// of the sphere.
+	SpherePoint
+	// A "+" marker, whose arms are a quarter of Size thick.
+	CrossMarker
+	// A filled square marker aligned with the screen.
+	SquareMarker
+	// A filled upward equilateral triangle marker inscribed in the circle of diameter Size.
+	TriangleMarker
+)
+
+func (ps PointShape) validate() error {
+	if ps < DiscPoint || ps > TriangleMarker {
+		return fmt.Errorf("unknown point shape %v", int(ps))
+	}
+	return nil
+}
+
+// SpacePoint represents a point primitive drawn at a 3D position, which is depth sorted together with the paths.
+type SpacePoint struct {
+	Pos   *graphix.Vec3
+	Color color.Color
+	Shape PointShape
+	// Diameter of discs and spheres, or side length of markers, in pixels unless WorldSize.
+	Size float64
+	// Whether Size is in world units, so that the point scales with its distance from the camera.
+	WorldSize bool
+}
+
+// Light direction of SpherePoint in screen coordinates (right for +x, down for +y, toward the camera for +z).
+var sphereLight = func() graphix.Vec3 {
+	l := graphix.NewVec3(-1, -1, 2)
+	return *l.Scale(l, 1/l.Norm())
+}()
+
+// Fraction of the color of a SpherePoint which is not affected by the light.
+const sphereAmbient = .25
+
+// projectedPoint is a point primitive to draw, projected on the screen.
+type projectedPoint struct {
+	// Center in screen coordinates with z depth.
+	p graphix.Projection
+	// Radius in pixels, and in units of z depth for spheres.
+	r, zr float64
+	shape PointShape
+	// Premultiplied color.
+	c color.RGBA64
+}
+
+// Projects pt into out, returning false if pt is not visible since it is outside of the z-clip planes.
+func (pp *pathProjector) projectPoint(pt *SpacePoint, out *projectedPoint) bool {
+	cam := pp.settings.Camera
+	v, u := &pp.v1, &pp.v2
+	cam.ViewTransform().Apply(v, pt.Pos)
+	p := &out.p
+	cam.Projector().Project(p, v)
+	// Also rejects NaN z-distances.
+	if !(p[2] >= cam.Projector().NearZClip() && p[2] <= farZClip(cam.Projector())) {
+		return false
+	}
+	cam.Screen().Map(p, p)
+
+	// Pixels per world unit around pt, averaged over the view-space x and y directions.
+	d := 1e-3 * max(v.Norm(), 1)
+	var q graphix.Projection
+	scale := 0.
+	for axis := range 2 {
+		*u = *v
+		u[axis] += d
+		cam.Projector().Project(&q, u)
+		cam.Screen().Map(&q, &q)
+		scale += math.Hypot(q[0]-p[0], q[1]-p[1]) / d / 2
+	}
+	if pt.WorldSize {
+		out.r, out.zr = pt.Size/2*scale, pt.Size/2
+	} else {
+		out.r = pt.Size / 2 * pp.widthScale
+		out.zr = 0
+		if scale > 0 {
+			out.zr = out.r / scale
+		}
+	}
+	out.shape = pt.Shape
+	out.c = toRGBA64(pt.Color)
+	return true
+}
+
+// Projects with pp and rasterizes the points of its settings with index i for which keep(i) is true into rec.
+// It returns early with the error of ctx once ctx is done.
+func rasterizePoints(
+	ctx context.Context,
+	pp *pathProjector,
+	rasterizer *raster.Rasterizer,
+	rec *strokeRecorder,
+	keep func(i int) bool,
+) error {
+	var pt projectedPoint
+	for i, sp := range pp.settings.Points {
+		if !keep(i) {
+			continue
+		}
+		if (i+1)%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if pp.projectPoint(sp, &pt) {
+			rasterizePoint(rasterizer, rec, &pt)
+		}
+	}
+	return nil
+}
+
+// Rasterizes the point pt into rec.
+func rasterizePoint(rasterizer *raster.Rasterizer, rec *strokeRecorder, pt *projectedPoint) {
+	rasterizer.Clear()
+	c, r := &pt.p, pt.r
+	var outline []fixed.Point26_6
+	// Appends the points (x,y) relative to the center c.
+	appendRel := func(xys ...float64) {
+		for i := 0; i < len(xys); i += 2 {
+			outline = append(outline, fixed.Point26_6{X: toFixed(c[0] + xys[i]), Y: toFixed(c[1] + xys[i+1])})
+		}
+	}
+	switch pt.shape {
+	case DiscPoint, SpherePoint:
+		outline = appendArc(outline, c, r, 0, 2*math.Pi)
+	case CrossMarker:
+		t := r / 4
+		appendRel(-t, -r, t, -r, t, -t, r, -t, r, t, t, t, t, r, -t, r, -t, t, -r, t, -r, -t, -t, -t)
+	case SquareMarker:
+		appendRel(-r, -r, r, -r, r, r, -r, r)
+	case TriangleMarker:
+		// Vertices at the top, lower right and lower left of the circle of radius r.
+		h, w := r/2, r*math.Sqrt(3)/2
+		appendRel(0, -r, w, h, -w, h)
+	}
+	addOutline(rasterizer, outline)
+	rec.prepareForPoint(pt)
+	rasterizer.Rasterize(rec)
+}
@@ -0,0 +1,129 @@
+package zraster
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/euphoricrhino/go-common/graphix"
+)
+
+// StereoLayout defines how the left and right images of a stereo render are composed.
+type StereoLayout int
+
+const (
+	// Red/cyan anaglyph: the red channel comes from the left image, green and blue from the right image.
+	AnaglyphRedCyan StereoLayout = iota
+	// Left and right images next to each other, doubling the width.
+	SideBySide
+	// Left image over the right image, doubling the height.
+	OverUnder
+)
+
+func (sl StereoLayout) validate() error {
+	if sl < AnaglyphRedCyan || sl > OverUnder {
+		return fmt.Errorf("unknown stereo layout %v", int(sl))
+	}
+	return nil
+}
+
+// StereoSettings defines the settings for RunStereo.
+type StereoSettings struct {
+	// Distance between the two eyes, in world units, which must be non-negative and finite.
+	Interocular float64
+	// Z-distance in front of the camera with zero parallax, which must be positive, see graphix.NewStereoRig.
+	Convergence float64
+	Layout      StereoLayout
+}
+
+// RunStereo renders the paths with the left and right cameras of a stereo rig derived from settings.Camera,
+// and composes the two images according to stereo.Layout.
+// It panics if settings are invalid, see RunStereoContext.
+func RunStereo(settings Settings, stereo StereoSettings) draw.Image {
+	img, err := RunStereoContext(context.Background(), settings, stereo)
+	if err != nil {
+		panic(fmt.Sprintf("failed to rasterize: %v", err))
+	}
+	return img
+}
+
+// RunStereoContext is like RunStereo, but returns an error instead of panicking, see RunContext.
+// Besides settings, stereo is validated according to graphix.NewCheckedStereoRig.
+func RunStereoContext(ctx context.Context, settings Settings, stereo StereoSettings) (draw.Image, error) {
+	rig, err := graphix.NewCheckedStereoRig(settings.Camera, stereo.Interocular, stereo.Convergence)
+	if err != nil {
+		return nil, err
+	}
+	if err := stereo.Layout.validate(); err != nil {
+		return nil, err
+	}
+	if err := settings.Alpha.validate(); err != nil {
+		return nil, err
+	}
+	// Compose the premultiplied images, which are converted according to the alpha mode afterwards.
+	alpha := settings.Alpha
+	settings.Alpha = PremultipliedAlpha
+	settings.Camera = rig.Left()
+	left, err := RunContext(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	settings.Camera = rig.Right()
+	right, err := RunContext(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	return composeStereo(asCanvas(left), asCanvas(right), stereo.Layout).output(alpha), nil
+}
+
+// ComposeStereo composes the left and right images of the same size according to layout.
+// It panics if layout is unknown.
+// The composed image is an *image.RGBA64 or a *graphix.FloatImage with premultiplied colors if left is so,
+// otherwise an *image.RGBA.
+func ComposeStereo(left, right image.Image, layout StereoLayout) draw.Image {
+	if err := layout.validate(); err != nil {
+		panic(err)
+	}
+	lc := asCanvas(left)
+	if lc == nil {
+		lc = newCanvas(RGBA8, left.Bounds())
+		draw.Draw(lc, lc.Bounds(), left, left.Bounds().Min, draw.Src)
+	}
+	rc := asCanvas(right)
+	if rc == nil || formatOf(rc) != formatOf(lc) {
+		rc = newCanvas(formatOf(lc), right.Bounds())
+		draw.Draw(rc, rc.Bounds(), right, right.Bounds().Min, draw.Src)
+	}
+	return composeStereo(lc, rc, layout).output(PremultipliedAlpha)
+}
+
+func composeStereo(left, right canvas, layout StereoLayout) canvas {
+	lb, rb := left.Bounds(), right.Bounds()
+	w, h := lb.Dx(), lb.Dy()
+	var cv canvas
+	// Offsets of the right image in the composed image.
+	var dx, dy int
+	switch layout {
+	case SideBySide:
+		cv = newCanvas(formatOf(left), image.Rect(0, 0, 2*w, h))
+		dx = w
+	case OverUnder:
+		cv = newCanvas(formatOf(left), image.Rect(0, 0, w, 2*h))
+		dy = h
+	default:
+		cv = newCanvas(formatOf(left), image.Rect(0, 0, w, h))
+	}
+	for y := range h {
+		for x := range w {
+			lp, rp := left.get(lb.Min.X+x, lb.Min.Y+y), right.get(rb.Min.X+x, rb.Min.Y+y)
+			if layout == SideBySide || layout == OverUnder {
+				cv.set(x, y, lp)
+				cv.set(x+dx, y+dy, rp)
+				continue
+			}
+			cv.set(x, y, [4]float64{lp[0], rp[1], rp[2], max(lp[3], rp[3])})
+		}
+	}
+	return cv
+}
@@ -1,21 +1,21 @@
 package zraster
 
 import (
+	"image"
 	"image/color"
+	"math"
 
 	"github.com/euphoricrhino/go-common/graphix"
 	"github.com/golang/freetype/raster"
 )
 
-// zBuffer stores for each pixel a list of zColors.
-type zBuffer [][]*zColor
-
 // strokeRecorder implements raster.Painter so every rasterized stroke will be recorded
 // with the z-distance value of the pixels, which will later be sorted and rendered in order.
 type strokeRecorder struct {
-	width   int
-	height  int
-	zbuf    zBuffer
+	// Pixels outside bounds are not recorded.
+	bounds image.Rectangle
+	// Fragments recorded in order, which is kept when they are grouped by pixel.
+	frags   []fragment
 	p1      *graphix.Projection
 	p2      *graphix.Projection
 	dd      float64
@@ -23,20 +23,27 @@ type strokeRecorder struct {
 	strokeG uint32
 	strokeB uint32
 	strokeA uint32
+	// Whether the stroke color changes along the line segment, from c1 by dc.
+	smooth bool
+	c1, dc [4]float64
+	// The point being rasterized instead of a line segment stroke, if not nil.
+	point *projectedPoint
 
-	// These maps stores all the pixels touched by the previous stroke and the current stroke.
+	// These maps stores all the pixels touched by the previous stroke and the current stroke,
+	// together with the index in frags of the fragment recorded for the pixel.
 	front   int
-	touched [2]map[int]struct{}
+	touched [2]map[int32]int
 }
 
-func newStrokeRecorder(width, height int) *strokeRecorder {
+// Creates a strokeRecorder recording the pixels within bounds, appending to frags.
+// The fragments are indexed by pixel in row-major order within bounds.
+func newStrokeRecorder(bounds image.Rectangle, frags []fragment) *strokeRecorder {
 	rec := &strokeRecorder{
-		width:  width,
-		height: height,
-		zbuf:   make(zBuffer, width*height),
+		bounds: bounds,
+		frags:  frags,
 	}
-	rec.touched[0] = make(map[int]struct{})
-	rec.touched[1] = make(map[int]struct{})
+	rec.touched[0] = make(map[int32]int)
+	rec.touched[1] = make(map[int32]int)
 	return rec
 }
 
@@ -46,26 +53,69 @@ func (rec *strokeRecorder) resetForPath() {
 }
 
 // Prepares the recorder for the rasterization of the next line segment stroke by
-// storing the endpoints' position and stroke color.
-func (rec *strokeRecorder) prepareForRasterization(p1, p2 *graphix.Projection, color color.Color) {
+// storing the endpoints' position and stroke colors.
+func (rec *strokeRecorder) prepareForRasterization(p1, p2 *graphix.Projection, c1, c2 *color.RGBA64) {
 	// Prepare the recorder for this stroke.
+	rec.point = nil
 	rec.p1 = p1
 	rec.p2 = p2
 	dx, dy := p1[0]-p2[0], p1[1]-p2[1]
 	rec.dd = dx*dx + dy*dy
-	rec.strokeR, rec.strokeG, rec.strokeB, rec.strokeA = color.RGBA()
+	rec.strokeR, rec.strokeG, rec.strokeB, rec.strokeA = uint32(c1.R), uint32(c1.G), uint32(c1.B), uint32(c1.A)
+	rec.smooth = *c1 != *c2
+	if rec.smooth {
+		rec.c1 = [4]float64{float64(c1.R), float64(c1.G), float64(c1.B), float64(c1.A)}
+		rec.dc = [4]float64{
+			float64(c2.R) - rec.c1[0],
+			float64(c2.G) - rec.c1[1],
+			float64(c2.B) - rec.c1[2],
+			float64(c2.A) - rec.c1[3],
+		}
+	}
 	// Swap the front/back maps.
 	rec.front = 1 - rec.front
 	// Clear the touched map for the new stroke.
 	clear(rec.touched[rec.front])
 }
 
-// Update the z-buffer with the pixel touched by the rasterizer.
-func (rec *strokeRecorder) updateZBuf(x, y int, r, g, b, a uint32) {
+// Prepares the recorder for the rasterization of the point pt.
+func (rec *strokeRecorder) prepareForPoint(pt *projectedPoint) {
+	rec.point = pt
+	rec.strokeR, rec.strokeG, rec.strokeB, rec.strokeA = uint32(pt.c.R), uint32(pt.c.G), uint32(pt.c.B), uint32(pt.c.A)
+}
+
+// Records the pixel of the point being rasterized, which does not overlap with anything else of the same path.
+func (rec *strokeRecorder) recordPoint(x, y int, alpha uint32) {
+	pt := rec.point
+	z := pt.p[2]
+	r, g, b, a := rec.strokeR, rec.strokeG, rec.strokeB, rec.strokeA
+	if pt.shape == SpherePoint && pt.r > 0 {
+		// The normal of the front surface of the sphere at the center of the pixel.
+		nx, ny := (float64(x)+.5-pt.p[0])/pt.r, (float64(y)+.5-pt.p[1])/pt.r
+		nz := math.Sqrt(max(1-nx*nx-ny*ny, 0))
+		z -= pt.zr * nz
+		shade := sphereAmbient + (1-sphereAmbient)*max(nx*sphereLight[0]+ny*sphereLight[1]+nz*sphereLight[2], 0)
+		r = uint32(math.Round(float64(r) * shade))
+		g = uint32(math.Round(float64(g) * shade))
+		b = uint32(math.Round(float64(b) * shade))
+	}
+	rec.frags = append(rec.frags, fragment{
+		pixel:  int32((y-rec.bounds.Min.Y)*rec.bounds.Dx() + x - rec.bounds.Min.X),
+		zColor: zColor{r: r * alpha, g: g * alpha, b: b * alpha, a: a * alpha, z: float32(z)},
+	})
+}
+
+// Update the z-buffer with the pixel touched by the rasterizer with coverage alpha.
+func (rec *strokeRecorder) updateZBuf(x, y int, alpha uint32) {
+	if rec.point != nil {
+		rec.recordPoint(x, y, alpha)
+		return
+	}
 	// Computes the z depth of the pixel by linearly interpolating between the two end points.
 	// Due to rasterizing, (x,y) may not be on the line connecting the two end points.
 	// The projection point from (x,y) to this line is used to interpolate z depth.
 	z := rec.p1[2]
+	t := 0.
 	// Degenerate case.
 	if rec.dd == 0 {
 		if z < rec.p2[2] {
@@ -76,55 +126,57 @@ func (rec *strokeRecorder) updateZBuf(x, y int, r, g, b, a uint32) {
 		dd1 := dx1*dx1 + dy1*dy1
 		dx2, dy2 := float64(x)-rec.p2[0], float64(y)-rec.p2[1]
 		dd2 := dx2*dx2 + dy2*dy2
-		t := ((dd1-dd2)/rec.dd + 1) / 2
+		t = ((dd1-dd2)/rec.dd + 1) / 2
 		z = rec.p1[2] + t*(rec.p2[2]-rec.p1[2])
 	}
+	r, g, b, a := rec.strokeR, rec.strokeG, rec.strokeB, rec.strokeA
+	if rec.smooth {
+		// The color is interpolated with the same parameter as z, clamped to the end points for the caps.
+		t = min(max(t, 0), 1)
+		r = uint32(math.Round(rec.c1[0] + t*rec.dc[0]))
+		g = uint32(math.Round(rec.c1[1] + t*rec.dc[1]))
+		b = uint32(math.Round(rec.c1[2] + t*rec.dc[2]))
+		a = uint32(math.Round(rec.c1[3] + t*rec.dc[3]))
+	}
+	r, g, b, a = r*alpha, g*alpha, b*alpha, a*alpha
 
-	i := y*rec.width + x
-	if _, found := rec.touched[1-rec.front][i]; found {
-		lastIdx := len(rec.zbuf[i]) - 1
+	i := int32((y-rec.bounds.Min.Y)*rec.bounds.Dx() + x - rec.bounds.Min.X)
+	if last, found := rec.touched[1-rec.front][i]; found {
 		// The last stroke of the same path touched the same pixel, we will not record both to avoid making the
 		// shared vertex brighter than other part of the path. We simply keep the one with greater opacity.
-		if a > rec.zbuf[i][lastIdx].a {
-			rec.zbuf[i][lastIdx].r = r
-			rec.zbuf[i][lastIdx].g = g
-			rec.zbuf[i][lastIdx].b = b
-			rec.zbuf[i][lastIdx].a = a
-			rec.zbuf[i][lastIdx].z = z
+		if zc := &rec.frags[last].zColor; a > zc.a {
+			*zc = zColor{r: r, g: g, b: b, a: a, z: float32(z)}
 		}
+		rec.touched[rec.front][i] = last
 	} else {
-		rec.zbuf[i] = append(rec.zbuf[i], &zColor{
-			r: r,
-			g: g,
-			b: b,
-			a: a,
-			z: z,
+		rec.frags = append(rec.frags, fragment{
+			pixel:  i,
+			zColor: zColor{r: r, g: g, b: b, a: a, z: float32(z)},
 		})
+		rec.touched[rec.front][i] = len(rec.frags) - 1
 	}
-	rec.touched[rec.front][i] = struct{}{}
 }
 
 // Paint make strokeRecorder implement raster.Painter so we get the call for each rasterized span.
 func (rec *strokeRecorder) Paint(ss []raster.Span, done bool) {
 	for _, s := range ss {
-		if s.Y < 0 {
+		if s.Y < rec.bounds.Min.Y {
 			continue
 		}
-		if s.Y >= rec.height {
+		if s.Y >= rec.bounds.Max.Y {
 			return
 		}
-		if s.X0 < 0 {
-			s.X0 = 0
+		if s.X0 < rec.bounds.Min.X {
+			s.X0 = rec.bounds.Min.X
 		}
-		if s.X1 > rec.width {
-			s.X1 = rec.width
+		if s.X1 > rec.bounds.Max.X {
+			s.X1 = rec.bounds.Max.X
 		}
 		if s.X0 >= s.X1 {
 			continue
 		}
-		r, g, b, a := rec.strokeR*s.Alpha, rec.strokeG*s.Alpha, rec.strokeB*s.Alpha, rec.strokeA*s.Alpha
 		for x := s.X0; x < s.X1; x++ {
-			rec.updateZBuf(x, s.Y, r, g, b, a)
+			rec.updateZBuf(x, s.Y, s.Alpha)
 		}
 	}
 }
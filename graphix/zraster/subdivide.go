@@ -0,0 +1,48 @@
+package zraster
+
+import (
+	"math"
+
+	"github.com/euphoricrhino/go-common/graphix"
+)
+
+const (
+	// Maximum deviation in pixels between a projected curve and the straight piece used to stroke it.
+	curveTolerance = .25
+	// Maximum depth of recursive bisection of a segment projected by a curved projector.
+	maxSubdivisionDepth = 16
+)
+
+// subdivide recursively bisects the segment from a to b (in canonical camera coordinates) until the projection
+// of each piece deviates from a straight line by no more than curveTolerance pixels, calling emit for each piece in order.
+// Pieces still crossing a discontinuity of the projection at the maximum depth are dropped.
+func subdivide(
+	pr graphix.CurvedProjector,
+	sc *graphix.Screen,
+	a, b *graphix.Vec3,
+	depth int,
+	emit func(a, b *graphix.Vec3),
+) {
+	var m graphix.Vec3
+	var pa, pb, pm graphix.Projection
+	m.Add(a, b)
+	m.Scale(&m, .5)
+	pr.Project(&pa, a)
+	pr.Project(&pb, b)
+	pr.Project(&pm, &m)
+	disc := pr.Discontinuous(&pa, &pb)
+	if !disc {
+		sc.Map(&pa, &pa)
+		sc.Map(&pb, &pb)
+		sc.Map(&pm, &pm)
+		dev := math.Hypot(pm[0]-(pa[0]+pb[0])/2, pm[1]-(pa[1]+pb[1])/2)
+		if dev <= curveTolerance || depth == maxSubdivisionDepth {
+			emit(a, b)
+			return
+		}
+	} else if depth == maxSubdivisionDepth {
+		return
+	}
+	subdivide(pr, sc, a, &m, depth+1, emit)
+	subdivide(pr, sc, &m, b, depth+1, emit)
+}
@@ -0,0 +1,163 @@
+package zraster
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/euphoricrhino/go-common/graphix"
+)
+
+// Filter defines the reconstruction filter used to downsample a supersampled render.
+type Filter int
+
+const (
+	// Averages the subsamples within each pixel.
+	BoxFilter Filter = iota
+	// Weights the subsamples linearly by their distance to the pixel center, up to 1 pixel away.
+	TentFilter
+	// Weights the subsamples by the Lanczos kernel with 3 lobes, which is the sharpest but may ring around edges.
+	LanczosFilter
+)
+
+func (f Filter) validate() error {
+	if f < BoxFilter || f > LanczosFilter {
+		return fmt.Errorf("unknown filter %v", int(f))
+	}
+	return nil
+}
+
+// Returns the radius in pixels beyond which the filter weight is 0.
+func (f Filter) radius() float64 {
+	switch f {
+	case TentFilter:
+		return 1
+	case LanczosFilter:
+		return 3
+	default:
+		return .5
+	}
+}
+
+// Returns the filter weight at distance d in pixels from the pixel center.
+func (f Filter) weight(d float64) float64 {
+	d = math.Abs(d)
+	if d >= f.radius() {
+		return 0
+	}
+	switch f {
+	case TentFilter:
+		return 1 - d
+	case LanczosFilter:
+		return sinc(d) * sinc(d/3)
+	default:
+		return 1
+	}
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+// filterTaps is the normalized weights of the consecutive subsamples starting at start contributing to a pixel.
+type filterTaps struct {
+	start   int
+	weights []float64
+}
+
+// Computes the taps of each of size pixels along one dimension downsampled from n×size subsamples.
+func newFilterTaps(f Filter, size, n int) []filterTaps {
+	taps := make([]filterTaps, size)
+	r := f.radius()
+	for i := range taps {
+		c := float64(i) + .5
+		start := max(int(math.Floor((c-r)*float64(n))), 0)
+		end := min(int(math.Ceil((c+r)*float64(n))), size*n)
+		weights := make([]float64, end-start)
+		sum := 0.0
+		for s := start; s < end; s++ {
+			// Subsample centers in pixel units.
+			w := f.weight((float64(s)+.5)/float64(n) - c)
+			weights[s-start] = w
+			sum += w
+		}
+		// Normalize so that the weights of the subsamples beyond the edges are redistributed.
+		for j := range weights {
+			weights[j] /= sum
+		}
+		taps[i] = filterTaps{start: start, weights: weights}
+	}
+	return taps
+}
+
+// Renders the paths with settings.Supersampling×settings.Supersampling subsamples per pixel, then downsamples them
+// with settings.Filter and composites the result over the background.
+func runSupersampled(ctx context.Context, settings *Settings) (canvas, error) {
+	n := settings.Supersampling
+	sub := *settings
+	sub.Camera = graphix.NewCamera(
+		settings.Camera.ViewTransform(),
+		settings.Camera.Projector(),
+		graphix.NewScaledScreen(settings.Camera.Screen(), n),
+	)
+	sub.TileSize *= n
+	sub.Background = image.Transparent
+	sub.Supersampling = 1
+	// Line widths and point sizes are in pixels, so they scale with the screen.
+	subCv, err := render(ctx, &sub, float64(n))
+	if err != nil {
+		return nil, err
+	}
+
+	cv := newBlankCanvas(settings)
+	width, height := cv.Bounds().Dx(), cv.Bounds().Dy()
+	xtaps, ytaps := newFilterTaps(settings.Filter, width, n), newFilterTaps(settings.Filter, height, n)
+	if err := runWorkers(ctx, settings.Workers, func(ctx context.Context, w int) error {
+		// Subsample rows filtered vertically.
+		row := make([][4]float64, width*n)
+		for y := w; y < height; y += settings.Workers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			clear(row)
+			yt := &ytaps[y]
+			for j, wy := range yt.weights {
+				for x := range row {
+					px := subCv.get(x, yt.start+j)
+					for c := range 4 {
+						row[x][c] += wy * px[c]
+					}
+				}
+			}
+			for x := range width {
+				xt := &xtaps[x]
+				var px [4]float64
+				for j, wx := range xt.weights {
+					for c := range 4 {
+						px[c] += wx * row[xt.start+j][c]
+					}
+				}
+				// Negative lobes may overshoot, keep the colors premultiplied.
+				px[3] = math.Min(math.Max(px[3], 0), 1)
+				for c := range 3 {
+					px[c] = math.Min(math.Max(px[c], 0), px[3])
+				}
+				// Composite over the background.
+				bg := cv.get(x, y)
+				for c := range 4 {
+					px[c] += (1 - px[3]) * bg[c]
+				}
+				cv.set(x, y, px)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return cv, nil
+}
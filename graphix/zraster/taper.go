@@ -0,0 +1,59 @@
+package zraster
+
+import (
+	"math"
+
+	"github.com/euphoricrhino/go-common/graphix"
+	"github.com/golang/freetype/raster"
+	"golang.org/x/image/math/fixed"
+)
+
+// Maximum deviation in pixels between a round cap and the polygon approximating it.
+const capTolerance = .05
+
+// Adds to rasterizer the outline of the convex hull of the discs centered at p1 and p2 (in screen coordinates) with
+// radii r1 and r2, which is a stroke from p1 to p2 with round caps, whose width changes linearly.
+// Consecutive tapered strokes sharing an end point and its radius join without seams, since they share the disc.
+func addTaperedStroke(rasterizer *raster.Rasterizer, p1, p2 *graphix.Projection, r1, r2 float64) {
+	var outline []fixed.Point26_6
+	dx, dy := p2[0]-p1[0], p2[1]-p1[1]
+	d := math.Hypot(dx, dy)
+	if d <= math.Abs(r1-r2) {
+		// One disc contains the other.
+		if r1 > r2 {
+			outline = appendArc(outline, p1, r1, 0, 2*math.Pi)
+		} else {
+			outline = appendArc(outline, p2, r2, 0, 2*math.Pi)
+		}
+	} else {
+		// The outer tangent lines touch the discs at angles ±beta from the direction from p1 to p2.
+		alpha := math.Atan2(dy, dx)
+		beta := math.Acos((r1 - r2) / d)
+		// The far side of the disc around p1, then the far side of the disc around p2.
+		outline = appendArc(outline, p1, r1, alpha+beta, alpha+2*math.Pi-beta)
+		outline = appendArc(outline, p2, r2, alpha-beta, alpha+beta)
+	}
+	addOutline(rasterizer, outline)
+}
+
+// Adds to rasterizer the closed polygon with the vertices of outline.
+func addOutline(rasterizer *raster.Rasterizer, outline []fixed.Point26_6) {
+	rasterizer.Start(outline[0])
+	for _, fp := range outline[1:] {
+		rasterizer.Add1(fp)
+	}
+	rasterizer.Add1(outline[0])
+}
+
+// Appends the arc of the circle around c with radius r from angle a0 to a1 as a polyline to outline.
+func appendArc(outline []fixed.Point26_6, c *graphix.Projection, r, a0, a1 float64) []fixed.Point26_6 {
+	n := 1
+	if r > capTolerance {
+		n = max(int(math.Ceil((a1-a0)/(2*math.Acos(1-capTolerance/r)))), 1)
+	}
+	for i := 0; i <= n; i++ {
+		sin, cos := math.Sincos(a0 + (a1-a0)*float64(i)/float64(n))
+		outline = append(outline, fixed.Point26_6{X: toFixed(c[0] + r*cos), Y: toFixed(c[1] + r*sin)})
+	}
+	return outline
+}
@@ -0,0 +1,189 @@
+package zraster
+
+import (
+	"context"
+	"errors"
+	"image"
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"github.com/golang/freetype/raster"
+)
+
+// Margin in pixels added around the line width when binning a line segment into tiles, which covers the
+// anti-aliased edge and the rounding of the end points to fixed point.
+const tileMargin = 2
+
+// screenSegment is a projected line segment to stroke in tiled mode.
+type screenSegment struct {
+	projectedSegment
+	// Index of the path in Settings.Paths.
+	path int32
+	// Index of the segment among the segments to stroke of the same path.
+	seq int32
+}
+
+// Renders the paths and points tile by tile, see Settings.TileSize.
+func runTiled(ctx context.Context, settings *Settings, widthScale float64) (canvas, error) {
+	width, height := settings.Camera.Screen().Width(), settings.Camera.Screen().Height()
+	ts := settings.TileSize
+	tilesX, tilesY := (width+ts-1)/ts, (height+ts-1)/ts
+
+	// Each worker projects a contiguous range of paths and points, so that the segments are ordered by path and the
+	// points by index once concatenated.
+	shards := make([][]screenSegment, settings.Workers)
+	pointShards := make([][]projectedPoint, settings.Workers)
+	if err := runWorkers(ctx, settings.Workers, func(ctx context.Context, w int) error {
+		pp := newPathProjector(settings, widthScale)
+		n := len(settings.Paths)
+		for i := w * n / settings.Workers; i < (w+1)*n/settings.Workers; i++ {
+			path := settings.Paths[i]
+			// Degenerate path.
+			if len(path.Segments) == 0 {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			seq := int32(0)
+			if err := pp.project(ctx, path, func(seg *projectedSegment) {
+				shards[w] = append(shards[w], screenSegment{projectedSegment: *seg, path: int32(i), seq: seq})
+				seq++
+			}); err != nil {
+				return err
+			}
+		}
+		var pt projectedPoint
+		n = len(settings.Points)
+		for i := w * n / settings.Workers; i < (w+1)*n/settings.Workers; i++ {
+			if (i+1)%cancelCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			if pp.projectPoint(settings.Points[i], &pt) {
+				pointShards[w] = append(pointShards[w], pt)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	segs, pts := slices.Concat(shards...), slices.Concat(pointShards...)
+	// Only the concatenated segments and points are needed from now on.
+	shards, pointShards = nil, nil
+	if len(segs)+len(pts) > math.MaxInt32 {
+		return nil, errors.New("too many line segments and points")
+	}
+
+	// Bin the segments and then the points into tiles with a counting sort, keeping them in order within each tile.
+	// Index i < len(segs) is segs[i], otherwise pts[i-len(segs)], where the indices of tile t are
+	// bins[offsets[t]:offsets[t+1]].
+	tileRange := func(i int) (image.Rectangle, bool) {
+		var x0, x1, y0, y1 float64
+		if i < len(segs) {
+			seg := &segs[i]
+			r := max(seg.w1, seg.w2)/2 + tileMargin
+			x0, x1 = math.Min(seg.p1[0], seg.p2[0])-r, math.Max(seg.p1[0], seg.p2[0])+r
+			y0, y1 = math.Min(seg.p1[1], seg.p2[1])-r, math.Max(seg.p1[1], seg.p2[1])+r
+		} else {
+			pt := &pts[i-len(segs)]
+			r := pt.r + tileMargin
+			x0, x1, y0, y1 = pt.p[0]-r, pt.p[0]+r, pt.p[1]-r, pt.p[1]+r
+		}
+		// Also rejects NaN coordinates.
+		if !(x1 >= 0 && x0 < float64(width) && y1 >= 0 && y0 < float64(height)) {
+			return image.Rectangle{}, false
+		}
+		x0, y0 = math.Max(x0, 0), math.Max(y0, 0)
+		x1, y1 = math.Min(x1, float64(width-1)), math.Min(y1, float64(height-1))
+		return image.Rect(int(x0)/ts, int(y0)/ts, int(x1)/ts+1, int(y1)/ts+1), true
+	}
+	offsets := make([]int, tilesX*tilesY+1)
+	for i := range len(segs) + len(pts) {
+		if tr, ok := tileRange(i); ok {
+			for ty := tr.Min.Y; ty < tr.Max.Y; ty++ {
+				for tx := tr.Min.X; tx < tr.Max.X; tx++ {
+					offsets[ty*tilesX+tx]++
+				}
+			}
+		}
+	}
+	// Turn the counts into the end offset of each tile.
+	for t := 1; t < len(offsets); t++ {
+		offsets[t] += offsets[t-1]
+	}
+	bins := make([]int32, offsets[len(offsets)-1])
+	// Scatter the segments backwards, which turns the end offset of each tile into its start offset.
+	for i := len(segs) + len(pts) - 1; i >= 0; i-- {
+		if tr, ok := tileRange(i); ok {
+			for ty := tr.Min.Y; ty < tr.Max.Y; ty++ {
+				for tx := tr.Min.X; tx < tr.Max.X; tx++ {
+					t := ty*tilesX + tx
+					offsets[t]--
+					bins[offsets[t]] = int32(i)
+				}
+			}
+		}
+	}
+
+	cv := newBlankCanvas(settings)
+	// Tiles are taken by the next available worker, since their loads vary a lot.
+	var next atomic.Int64
+	if err := runWorkers(ctx, settings.Workers, func(ctx context.Context, w int) error {
+		// The rasterizer covers the whole screen so that it rasterizes exactly as without tiling.
+		rasterizer := raster.NewRasterizer(width, height)
+		rasterizer.UseNonZeroWinding = true
+		arena := getSlice[fragment](&fragmentPool, 0)
+		defer fragmentPool.Put(arena)
+		for {
+			t := int(next.Add(1) - 1)
+			if t >= tilesX*tilesY {
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			bounds := image.Rect(t%tilesX*ts, t/tilesX*ts, t%tilesX*ts+ts, t/tilesX*ts+ts).Intersect(cv.Bounds())
+			rec := newStrokeRecorder(bounds, (*arena)[:0])
+			var prev *screenSegment
+			for j, i := range bins[offsets[t]:offsets[t+1]] {
+				if (j+1)%cancelCheckInterval == 0 {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+				}
+				if int(i) >= len(segs) {
+					rasterizePoint(rasterizer, rec, &pts[int(i)-len(segs)])
+					prev = nil
+					continue
+				}
+				seg := &segs[i]
+				// Consecutive strokes of the same path do not record a shared pixel twice (see strokeRecorder), a
+				// stroke not binned into this tile did not touch any pixel of it.
+				if prev == nil || prev.path != seg.path || prev.seq+1 != seg.seq {
+					rec.resetForPath()
+				}
+				rasterizeStroke(rasterizer, rec, &seg.projectedSegment)
+				prev = seg
+			}
+			*arena = rec.frags
+
+			fbp, err := groupByPixel([][]fragment{rec.frags}, bounds.Dx()*bounds.Dy())
+			if err != nil {
+				return err
+			}
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					resolve(cv, x, y, fbp.at((y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X), settings)
+				}
+			}
+			fbp.release()
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	return cv, nil
+}
@@ -4,11 +4,11 @@ package zraster
 type zColor struct {
 	// Color premultiplied with rasterizer span alpha.
 	r, g, b, a uint32
-	// Depth info.
-	z float64
+	// Depth info, single precision is enough to order fragments and keeps them compact.
+	z float32
 }
 
-type sortByZ []*zColor
+type sortByZ []zColor
 
 func (byz sortByZ) Len() int      { return len(byz) }
 func (byz sortByZ) Swap(i, j int) { byz[i], byz[j] = byz[j], byz[i] }
@@ -1,6 +1,9 @@
 package zraster
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -18,14 +21,22 @@ import (
 type SpaceVertex struct {
 	Pos   *graphix.Vec3
 	Color color.Color
+	// Optional line width at the vertex, 0 means the LineWidth of the path.
+	// The line width is interpolated linearly along the line segment up to the width at the next vertex.
+	Width float64
 }
 
 // SpacePath represents a 3D path.
 type SpacePath struct {
 	// All vertices up to the second-to-last vertex.
-	Segments  []*SpaceVertex
-	End       *graphix.Vec3
+	Segments []*SpaceVertex
+	End      *graphix.Vec3
+	// Line width in pixels of the vertices without their own width.
 	LineWidth float64
+	// Optional line width at End, 0 means LineWidth.
+	EndWidth float64
+	// Optional color at End with Settings.SmoothColors, nil means the color of the last vertex.
+	EndColor color.Color
 }
 
 // Settings defines the settings for zraster.Run().
@@ -33,89 +44,297 @@ type Settings struct {
 	Camera *graphix.Camera
 	// All the 3D paths to render.
 	Paths []*SpacePath
+	// All the point primitives to render, which are drawn after the paths at equal z-distances.
+	Points []*SpacePoint
 	// Concurrency.
 	Workers int
+	// Side length in pixels of the square screen tiles to render independently, 0 disables tiling.
+	// In tiled mode, the projected line segments are binned into the tiles they overlap, then each tile is rasterized,
+	// sorted and composited on its own by the next available worker, so only the fragments of the tiles in progress
+	// are kept at any time. The output is the same as rendering with a single worker without tiling.
+	TileSize int
+	// Background which the paths are composited over, with its top-left corner at the top-left corner of the screen.
+	// nil means opaque black. Use image.NewUniform for a background color, or image.Transparent for a transparent
+	// output.
+	Background image.Image
+	// Representation of the alpha channel of the output image.
+	Alpha AlphaMode
+	// Precision of compositing and type of the output image.
+	Format OutputFormat
+	// Optional attenuation of the paths by their z-distance from the camera.
+	DepthCue *DepthCue
+	// Whether to interpolate the colors and alpha of the vertices linearly along the line segments, instead of
+	// stroking each line segment with the color of its start vertex.
+	SmoothColors bool
+	// Number of subsamples per pixel along each dimension, 0 or 1 disables supersampling. With N×N subsamples, the
+	// paths and points are rendered at N times the screen dimensions with N times the line widths and point sizes in
+	// pixels, where the fragments are sorted and composited per subsample, then downsampled with Filter and
+	// composited over the background.
+	// TileSize applies to the pixels of the screen.
+	Supersampling int
+	// Reconstruction filter to downsample the subsamples.
+	Filter Filter
 }
 
-// Run implements a specialized rasterizer for 3D paths.
-// It renders the paths into an image while respecting their z-order.
+// Number of segments of a path rasterized between checks of the cancellation.
+const cancelCheckInterval = 1024
+
+// Validates the settings, so that workers can run without further checks.
+func (settings *Settings) validate() error {
+	cam := settings.Camera
+	if cam == nil {
+		return errors.New("camera must not be nil")
+	}
+	if cam.ViewTransform() == nil || cam.Projector() == nil || cam.Screen() == nil {
+		return errors.New("camera must have a view transform, a projector and a screen")
+	}
+	if w, h := cam.Screen().Width(), cam.Screen().Height(); w <= 0 || h <= 0 {
+		return fmt.Errorf("screen dimension must be positive, got %vx%v", w, h)
+	}
+	if settings.Workers <= 0 {
+		return fmt.Errorf("workers must be positive, got %v", settings.Workers)
+	}
+	if settings.TileSize < 0 {
+		return fmt.Errorf("tile size must not be negative, got %v", settings.TileSize)
+	}
+	if err := settings.Alpha.validate(); err != nil {
+		return err
+	}
+	if err := settings.Format.validate(); err != nil {
+		return err
+	}
+	if settings.DepthCue != nil {
+		if err := settings.DepthCue.validate(); err != nil {
+			return err
+		}
+	}
+	if settings.Supersampling < 0 {
+		return fmt.Errorf("supersampling must not be negative, got %v", settings.Supersampling)
+	}
+	// Pixels are indexed with int32.
+	if n := max(settings.Supersampling, 1); cam.Screen().Width()*cam.Screen().Height() > math.MaxInt32/n/n {
+		return fmt.Errorf(
+			"screen of %vx%v pixels with %vx%v subsamples is too large", cam.Screen().Width(), cam.Screen().Height(), n, n,
+		)
+	}
+	if err := settings.Filter.validate(); err != nil {
+		return err
+	}
+	for i, path := range settings.Paths {
+		if path == nil {
+			return fmt.Errorf("path %v must not be nil", i)
+		}
+		// Degenerate paths are skipped.
+		if len(path.Segments) == 0 {
+			continue
+		}
+		if !isValidWidth(path.LineWidth) {
+			return fmt.Errorf("path %v has invalid line width %v", i, path.LineWidth)
+		}
+		if !isValidWidth(path.EndWidth) {
+			return fmt.Errorf("path %v has invalid end width %v", i, path.EndWidth)
+		}
+		for j, sv := range path.Segments {
+			if sv == nil || sv.Pos == nil || sv.Color == nil {
+				return fmt.Errorf("path %v vertex %v must have a position and a color", i, j)
+			}
+			if !isFinite(sv.Pos) {
+				return fmt.Errorf("path %v vertex %v has non-finite position %v", i, j, *sv.Pos)
+			}
+			if !isValidWidth(sv.Width) {
+				return fmt.Errorf("path %v vertex %v has invalid width %v", i, j, sv.Width)
+			}
+		}
+		if path.End == nil {
+			return fmt.Errorf("path %v must have an end", i)
+		}
+		if !isFinite(path.End) {
+			return fmt.Errorf("path %v has non-finite end %v", i, *path.End)
+		}
+	}
+	for i, pt := range settings.Points {
+		if pt == nil {
+			return fmt.Errorf("point %v must not be nil", i)
+		}
+		if pt.Pos == nil || pt.Color == nil {
+			return fmt.Errorf("point %v must have a position and a color", i)
+		}
+		if !isFinite(pt.Pos) {
+			return fmt.Errorf("point %v has non-finite position %v", i, *pt.Pos)
+		}
+		if !isValidWidth(pt.Size) {
+			return fmt.Errorf("point %v has invalid size %v", i, pt.Size)
+		}
+		if err := pt.Shape.validate(); err != nil {
+			return fmt.Errorf("point %v: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func isValidWidth(w float64) bool { return w >= 0 && !math.IsInf(w, 1) }
+
+func isFinite(v *graphix.Vec3) bool {
+	for _, c := range v {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run implements a specialized rasterizer for 3D paths and points.
+// It renders the paths and points into an image while respecting their z-order.
+// It panics if settings are invalid, see RunContext.
 func Run(settings Settings) draw.Image {
-	chs := make([]chan zBuffer, settings.Workers)
-	for i := range chs {
-		chs[i] = make(chan zBuffer)
+	img, err := RunContext(context.Background(), settings)
+	if err != nil {
+		panic(fmt.Sprintf("failed to rasterize: %v", err))
 	}
+	return img
+}
 
-	for w := 0; w < settings.Workers; w++ {
-		go zworker(w, &settings, chs[w])
+// RunContext is like Run, but returns an error instead of panicking if settings are invalid, and stops rendering
+// as soon as ctx is done, in which case the error of ctx is returned. All goroutines spawned have exited by the
+// time RunContext returns.
+func RunContext(ctx context.Context, settings Settings) (draw.Image, error) {
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+	cv, err := render(ctx, &settings, 1)
+	if err != nil {
+		return nil, err
 	}
+	return cv.output(settings.Alpha), nil
+}
 
-	// Wait for all workers to finish updating their zbuffers.
-	zbufs := make([]zBuffer, len(chs))
-	for i, ch := range chs {
-		zbufs[i] = <-ch
+// Renders the paths and points into a premultiplied canvas, with all line widths and point sizes in pixels scaled by
+// widthScale.
+func render(ctx context.Context, settings *Settings, widthScale float64) (canvas, error) {
+	switch {
+	case settings.Supersampling > 1:
+		return runSupersampled(ctx, settings)
+	case settings.TileSize > 0:
+		return runTiled(ctx, settings, widthScale)
+	default:
+		return runShards(ctx, settings, widthScale)
 	}
+}
 
-	img := image.NewRGBA(
-		image.Rect(0, 0, settings.Camera.Screen().Width(), settings.Camera.Screen().Height()),
-	)
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0, 0, 0, 0xff}}, image.Point{}, draw.Src)
+// Renders the paths and points with each worker rasterizing a shard of them over the whole screen.
+func runShards(ctx context.Context, settings *Settings, widthScale float64) (canvas, error) {
+	// Each worker records the fragments of its own shard of paths.
+	frags := make([]*[]fragment, settings.Workers)
+	defer func() {
+		for _, fs := range frags {
+			if fs != nil {
+				fragmentPool.Put(fs)
+			}
+		}
+	}()
+	if err := runWorkers(ctx, settings.Workers, func(ctx context.Context, w int) error {
+		frags[w] = getSlice[fragment](&fragmentPool, 0)
+		var err error
+		*frags[w], err = zworker(ctx, w, settings, widthScale, *frags[w])
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	shards := make([][]fragment, len(frags))
+	for w, fs := range frags {
+		shards[w] = *fs
+	}
+	fbp, err := groupByPixel(shards, settings.Camera.Screen().Width()*settings.Camera.Screen().Height())
+	if err != nil {
+		return nil, err
+	}
+	defer fbp.release()
 
+	cv := newBlankCanvas(settings)
+	if err := runWorkers(ctx, settings.Workers, func(ctx context.Context, wk int) error {
+		for x := 0; x < settings.Camera.Screen().Width(); x++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for y := 0; y < settings.Camera.Screen().Height(); y++ {
+				i := y*settings.Camera.Screen().Width() + x
+				if i%settings.Workers != wk {
+					continue
+				}
+				// Resolve the fragments from all concurrent shards at pixel i.
+				resolve(cv, x, y, fbp.at(i), settings)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return cv, nil
+}
+
+// Sorts the fragments at pixel (x,y) by z, and composites them onto cv after depth cueing.
+func resolve(cv canvas, x, y int, frags []zColor, settings *Settings) {
+	sort.Sort(sortByZ(frags))
+	if settings.DepthCue != nil {
+		settings.DepthCue.attenuate(frags)
+	}
+	cv.composite(x, y, frags)
+}
+
+// Runs work for each of the workers concurrently and waits for all of them to return.
+// The context passed to work is canceled as soon as any worker fails, and the first error is returned.
+// A panicking worker fails with an error instead of crashing the program.
+func runWorkers(ctx context.Context, workers int, work func(ctx context.Context, w int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make([]error, workers)
 	var wg sync.WaitGroup
-	wg.Add(settings.Workers)
-	for w := 0; w < settings.Workers; w++ {
-		go func(wk int) {
-			for x := 0; x < settings.Camera.Screen().Width(); x++ {
-				for y := 0; y < settings.Camera.Screen().Height(); y++ {
-					i := y*settings.Camera.Screen().Width() + x
-					if i%settings.Workers != wk {
-						continue
-					}
-					// Merge and sort zbuffers from all concurrent shards at pixel i.
-					l := 0
-					for _, zbuf := range zbufs {
-						l += len(zbuf[i])
-					}
-					sorted := make([]*zColor, 0, l)
-					for _, zbuf := range zbufs {
-						sorted = append(sorted, zbuf[i]...)
-					}
-					sort.Sort(sortByZ(sorted))
-
-					idx := y*img.Stride + x*4
-
-					// Paint the pixels from far to near, multiplying the alpha at each layer in order.
-					for _, zc := range sorted {
-						dr := uint32(img.Pix[idx+0])
-						dg := uint32(img.Pix[idx+1])
-						db := uint32(img.Pix[idx+2])
-						da := uint32(img.Pix[idx+3])
-						a := (math.MaxUint16 - (zc.a / math.MaxUint16)) * 257 // 65535/255=257
-						img.Pix[idx+0] = uint8((dr*a + zc.r) / math.MaxUint16 >> 8)
-						img.Pix[idx+1] = uint8((dg*a + zc.g) / math.MaxUint16 >> 8)
-						img.Pix[idx+2] = uint8((db*a + zc.b) / math.MaxUint16 >> 8)
-						img.Pix[idx+3] = uint8((da*a + zc.a) / math.MaxUint16 >> 8)
-					}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[w] = fmt.Errorf("worker %v panicked: %v", w, r)
+					cancel()
 				}
+			}()
+			if errs[w] = work(ctx, w); errs[w] != nil {
+				cancel()
 			}
-			wg.Done()
-		}(w)
+		}()
 	}
 	wg.Wait()
-
-	return img
+	// Workers canceled due to the failure of another worker did not cause the failure, so prefer other errors.
+	var canceled error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+		canceled = err
+	}
+	return canceled
 }
 
-// One of the concurrent workers to work on a shard of the whole paths set, generating its own subset of z-buffers for each pixel.
-// All z-buffers of the same pixel will be merged and sorted subsequently.
-func zworker(w int, settings *Settings, ch chan<- zBuffer) {
+// One of the concurrent workers to work on a shard of the whole paths and points set, appending its fragments to frags
+// which is then returned. The fragments of all workers will be grouped by pixel and sorted subsequently.
+// The worker returns early with the error of ctx once ctx is done.
+func zworker(
+	ctx context.Context,
+	w int,
+	settings *Settings,
+	widthScale float64,
+	frags []fragment,
+) ([]fragment, error) {
 	width, height := settings.Camera.Screen().Width(), settings.Camera.Screen().Height()
 	rasterizer := raster.NewRasterizer(width, height)
 	rasterizer.UseNonZeroWinding = true
-	rec := newStrokeRecorder(width, height)
-	// Thread-local scratch area variables.
-	var v1, v2 graphix.Vec3
-	var p1, p2 graphix.Projection
-	var fp1, fp2 fixed.Point26_6
+	rec := newStrokeRecorder(image.Rect(0, 0, width, height), frags)
+	pp := newPathProjector(settings, widthScale)
 
 	for i, path := range settings.Paths {
 		// Work only on worker's own shard.
@@ -127,58 +346,206 @@ func zworker(w int, settings *Settings, ch chan<- zBuffer) {
 		if len(path.Segments) == 0 {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return rec.frags, err
+		}
 
 		rec.resetForPath()
-		// Strokes a 3D line segment from pos1 to pos2.
-		stroke := func(pos1, pos2 *graphix.Vec3, color color.Color) {
-			// View-transform to canonical camera coordinates.
-			settings.Camera.ViewTransform().Apply(&v1, pos1)
-			settings.Camera.ViewTransform().Apply(&v2, pos2)
-			// Do the projection.
-			settings.Camera.Projector().Project(&p1, &v1)
-			settings.Camera.Projector().Project(&p2, &v2)
-			nearZClip := settings.Camera.Projector().NearZClip()
-			// Discard the line if both ends are behind the z-clip plane.
-			if p1[2] < nearZClip && p2[2] < nearZClip {
-				return
-			}
-			// Clip the near end at the z-clip plane.
-			if p1[2] < nearZClip {
-				zclip(&p1, &p2, nearZClip)
-			} else if p2[2] < nearZClip {
-				zclip(&p2, &p1, nearZClip)
-			}
-			// Scale to screen dimensions.
-			settings.Camera.Screen().Map(&p1, &p1)
-			settings.Camera.Screen().Map(&p2, &p2)
-			toFixedPoint(&fp1, &p1)
-			toFixedPoint(&fp2, &p2)
-			// Stroke the rasterizer path.
-			var rasterPath raster.Path
-			rasterPath.Start(fp1)
-			rasterPath.Add1(fp2)
+		if err := pp.project(ctx, path, func(seg *projectedSegment) {
+			rasterizeStroke(rasterizer, rec, seg)
+		}); err != nil {
+			return rec.frags, err
+		}
+	}
 
-			rasterizer.Clear()
-			rasterizer.AddStroke(rasterPath, toFixed(path.LineWidth), nil, nil)
-			rec.prepareForRasterization(&p1, &p2, color)
-			rasterizer.Rasterize(rec)
+	err := rasterizePoints(ctx, pp, rasterizer, rec, func(i int) bool { return i%settings.Workers == w })
+	return rec.frags, err
+}
+
+// projectedSegment is a line segment to stroke, projected on the screen.
+type projectedSegment struct {
+	// End points in screen coordinates with z depth.
+	p1, p2 graphix.Projection
+	// Line widths at the end points.
+	w1, w2 float64
+	// Premultiplied colors at the end points.
+	c1, c2 color.RGBA64
+}
+
+// Rasterizes the line segment stroke seg into rec.
+func rasterizeStroke(rasterizer *raster.Rasterizer, rec *strokeRecorder, seg *projectedSegment) {
+	rasterizer.Clear()
+	if seg.w1 == seg.w2 {
+		var fp1, fp2 fixed.Point26_6
+		toFixedPoint(&fp1, &seg.p1)
+		toFixedPoint(&fp2, &seg.p2)
+		// Stroke the rasterizer path.
+		var rasterPath raster.Path
+		rasterPath.Start(fp1)
+		rasterPath.Add1(fp2)
+		rasterizer.AddStroke(rasterPath, toFixed(seg.w1), nil, nil)
+	} else {
+		addTaperedStroke(rasterizer, &seg.p1, &seg.p2, seg.w1/2, seg.w2/2)
+	}
+	rec.prepareForRasterization(&seg.p1, &seg.p2, &seg.c1, &seg.c2)
+	rasterizer.Rasterize(rec)
+}
+
+// pathProjector projects 3D paths into line segments in screen coordinates, clipped at the z-clip planes.
+type pathProjector struct {
+	settings   *Settings
+	curved     graphix.CurvedProjector
+	widthScale float64
+	// Scratch area variables.
+	v1, v2 graphix.Vec3
+	seg    projectedSegment
+}
+
+// Creates a pathProjector for settings, scaling all line widths and point sizes in pixels by widthScale.
+func newPathProjector(settings *Settings, widthScale float64) *pathProjector {
+	curved, _ := settings.Camera.Projector().(graphix.CurvedProjector)
+	return &pathProjector{settings: settings, curved: curved, widthScale: widthScale}
+}
+
+// Projects path, calling emit in order for each line segment to stroke. The segment passed to emit is only valid
+// until emit returns.
+// It returns early with the error of ctx once ctx is done.
+func (pp *pathProjector) project(ctx context.Context, path *SpacePath, emit func(seg *projectedSegment)) error {
+	cam := pp.settings.Camera
+	v1, v2, seg := &pp.v1, &pp.v2, &pp.seg
+	p1, p2 := &seg.p1, &seg.p2
+	// View-space end points, line widths and colors of the whole 3D line segment.
+	var o1, o2 graphix.Vec3
+	var ow1, ow2 float64
+	var oc1, oc2 color.RGBA64
+	// Returns the parameter in [0,1] of view-space point v along the 3D line segment.
+	paramAt := func(v *graphix.Vec3) float64 {
+		var d, dv graphix.Vec3
+		d.Sub(&o2, &o1)
+		dv.Sub(v, &o1)
+		return min(max(dv.Dot(&d)/d.Dot(&d), 0), 1)
+	}
+	// Projects a line segment from v1 to v2 in canonical camera coordinates.
+	projectView := func() {
+		// Do the projection.
+		cam.Projector().Project(p1, v1)
+		cam.Projector().Project(p2, v2)
+		near := cam.Projector().NearZClip()
+		far := farZClip(cam.Projector())
+		// Discard the line if both ends are behind the near z-clip plane or beyond the far z-clip plane.
+		if p1[2] < near && p2[2] < near || p1[2] > far && p2[2] > far {
+			return
+		}
+		// Clip the near end at the near z-clip plane.
+		if p1[2] < near {
+			zclip(cam.Projector(), v1, p1, v2, p2, near)
+		} else if p2[2] < near {
+			zclip(cam.Projector(), v2, p2, v1, p1, near)
 		}
+		// Clip the far end at the far z-clip plane.
+		if p1[2] > far {
+			zclip(cam.Projector(), v1, p1, v2, p2, far)
+		} else if p2[2] > far {
+			zclip(cam.Projector(), v2, p2, v1, p1, far)
+		}
+		// Scale to screen dimensions.
+		cam.Screen().Map(p1, p1)
+		cam.Screen().Map(p2, p2)
+		if ow1 == ow2 && oc1 == oc2 {
+			seg.w1, seg.w2, seg.c1, seg.c2 = ow1, ow1, oc1, oc1
+		} else {
+			t1, t2 := paramAt(v1), paramAt(v2)
+			seg.w1, seg.w2 = ow1+t1*(ow2-ow1), ow1+t2*(ow2-ow1)
+			seg.c1, seg.c2 = lerpRGBA64(&oc1, &oc2, t1), lerpRGBA64(&oc1, &oc2, t2)
+		}
+		emit(seg)
+	}
+	// Projects a 3D line segment from sv1 to pos2 with line width w2 and color c2 at pos2.
+	project := func(sv1 *SpaceVertex, pos2 *graphix.Vec3, w2 float64, c2 color.Color) {
+		// View-transform to canonical camera coordinates.
+		cam.ViewTransform().Apply(v1, sv1.Pos)
+		cam.ViewTransform().Apply(v2, pos2)
+		o1, o2 = *v1, *v2
+		ow1, ow2 = pp.width(path, sv1.Width), pp.width(path, w2)
+		oc1 = toRGBA64(sv1.Color)
+		if pp.settings.SmoothColors {
+			oc2 = toRGBA64(c2)
+		} else {
+			oc2 = oc1
+		}
+		if pp.curved == nil {
+			projectView()
+			return
+		}
+		// The projected segment is a curve, project it piecewise.
+		a, b := *v1, *v2
+		subdivide(pp.curved, cam.Screen(), &a, &b, 0, func(a, b *graphix.Vec3) {
+			*v1, *v2 = *a, *b
+			projectView()
+		})
+	}
 
-		i := 0
-		for ; i < len(path.Segments)-1; i++ {
-			stroke(path.Segments[i].Pos, path.Segments[i+1].Pos, path.Segments[i].Color)
+	i := 0
+	for ; i < len(path.Segments)-1; i++ {
+		// Long paths are also interruptible.
+		if (i+1)%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 		}
-		stroke(path.Segments[i].Pos, path.End, path.Segments[i].Color)
+		project(path.Segments[i], path.Segments[i+1].Pos, path.Segments[i+1].Width, path.Segments[i+1].Color)
+	}
+	endColor := path.EndColor
+	if endColor == nil {
+		endColor = path.Segments[i].Color
+	}
+	project(path.Segments[i], path.End, path.EndWidth, endColor)
+	return nil
+}
+
+func toRGBA64(c color.Color) color.RGBA64 {
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+}
+
+// Returns the linear interpolation between the premultiplied colors c1 and c2 at t∈[0,1].
+func lerpRGBA64(c1, c2 *color.RGBA64, t float64) color.RGBA64 {
+	lerp := func(a, b uint16) uint16 { return uint16(math.Round(float64(a) + t*(float64(b)-float64(a)))) }
+	return color.RGBA64{R: lerp(c1.R, c2.R), G: lerp(c1.G, c2.G), B: lerp(c1.B, c2.B), A: lerp(c1.A, c2.A)}
+}
+
+// Returns the line width in pixels of a vertex of path with optional width w.
+func (pp *pathProjector) width(path *SpacePath, w float64) float64 {
+	if w == 0 {
+		w = path.LineWidth
 	}
+	return w * pp.widthScale
+}
 
-	ch <- rec.zbuf
+// Returns the far clipping plane's z-coordinate of pr, which is +Inf if pr has no far clipping plane.
+func farZClip(pr graphix.Projector) float64 {
+	if fc, ok := pr.(graphix.FarClipper); ok {
+		return fc.FarZClip()
+	}
+	return math.Inf(1)
 }
 
-func zclip(near, far *graphix.Projection, z float64) {
-	t := (z - far[2]) / (near[2] - far[2])
-	near[0] = far[0] + t*(near[0]-far[0])
-	near[1] = far[1] + t*(near[1]-far[1])
-	near[2] = z
+// Moves the view-space point v with projection p, which is on the clipped side of the z-clip plane,
+// along the line towards w (with projection q) onto the plane.
+// For perspective projectors (see graphix.PerspectiveProjector), p is then projected again from v, otherwise the
+// projected coordinates are interpolated linearly.
+func zclip(pr graphix.Projector, v *graphix.Vec3, p *graphix.Projection, w *graphix.Vec3, q *graphix.Projection, z float64) {
+	t := (z - q[2]) / (p[2] - q[2])
+	v[0] = w[0] + t*(v[0]-w[0])
+	v[1] = w[1] + t*(v[1]-w[1])
+	v[2] = w[2] + t*(v[2]-w[2])
+	if per, ok := pr.(graphix.PerspectiveProjector); ok && per.Perspective() {
+		pr.Project(p, v)
+	} else {
+		p[0] = q[0] + t*(p[0]-q[0])
+		p[1] = q[1] + t*(p[1]-q[1])
+	}
+	p[2] = z
 }
 
 func toFixedPoint(fp *fixed.Point26_6, p *graphix.Projection) {
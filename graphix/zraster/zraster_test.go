@@ -2,10 +2,14 @@ package zraster
 
 import (
 	"bytes"
+	"context"
+	"image"
 	"image/color"
 	"image/png"
 	"math"
+	"math/rand/v2"
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/euphoricrhino/go-common/graphix"
@@ -13,7 +17,7 @@ import (
 )
 
 func zrasterTestHelper(t *testing.T, paths []*SpacePath, benchmarkFile string) {
-	img := Run(Settings{
+	settings := Settings{
 		Camera: graphix.NewCamera(
 			graphix.NewViewTransform(
 				graphix.NewVec3(0, 0, 8),
@@ -25,7 +29,8 @@ func zrasterTestHelper(t *testing.T, paths []*SpacePath, benchmarkFile string) {
 		),
 		Paths:   paths,
 		Workers: 1,
-	})
+	}
+	img := Run(settings)
 
 	benchmarkBytes, err := os.ReadFile(benchmarkFile)
 	assert.NoError(t, err)
@@ -33,6 +38,12 @@ func zrasterTestHelper(t *testing.T, paths []*SpacePath, benchmarkFile string) {
 	buf := bytes.NewBuffer(nil)
 	assert.NoError(t, png.Encode(buf, img))
 	assert.Equal(t, benchmarkBytes, buf.Bytes())
+
+	// Tiled rendering has the same output regardless of the tile size and the number of workers.
+	for _, ts := range []int{37, 64, 1000} {
+		settings.TileSize, settings.Workers = ts, 3
+		assert.Equal(t, img, Run(settings), "tile size %v", ts)
+	}
 }
 
 // Three lines, with cyclic overlapping relationship.
@@ -156,3 +167,857 @@ func TestZRasterRunZClip(t *testing.T) {
 	}}
 	zrasterTestHelper(t, paths, "testdata/zclip.png")
 }
+
+// Line segments will be clipped by the far z-clip plane.
+func TestZRasterRunFarZClip(t *testing.T) {
+	cam := newFOVPerspectiveTestCamera(t, 8, .1, 10, 200, 200)
+	color := color.NRGBA{R: 0, G: 0, B: 0xff, A: 0xff}
+	isBlack := func(img image.Image, pos *graphix.Vec3) bool {
+		p := cam.Projector().Project(graphix.BlankProjection(), cam.ViewTransform().Apply(graphix.BlankVec3(), pos))
+		cam.Screen().Map(p, p)
+		r, g, b, _ := img.At(int(p[0]), int(p[1])).RGBA()
+		return r == 0 && g == 0 && b == 0
+	}
+
+	// Entirely beyond the far plane.
+	paths := []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(-20, 0, -4), Color: color}},
+		End:       graphix.NewVec3(20, 0, -6),
+		LineWidth: 3,
+	}}
+	img := Run(Settings{Camera: cam, Paths: paths, Workers: 2})
+	assert.True(t, isBlack(img, graphix.NewVec3(0, 0, -5)))
+
+	// Crossing the far plane at z-distance 10.
+	paths = []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(-4, 0, 4), Color: color}},
+		End:       graphix.NewVec3(4, 0, -12),
+		LineWidth: 3,
+	}}
+	img = Run(Settings{Camera: cam, Paths: paths, Workers: 2})
+	assert.False(t, isBlack(img, graphix.NewVec3(-3, 0, 2)))
+	assert.False(t, isBlack(img, graphix.NewVec3(-1.2, 0, -1.6)))
+	assert.True(t, isBlack(img, graphix.NewVec3(0, 0, -4)))
+	assert.True(t, isBlack(img, graphix.NewVec3(3, 0, -10)))
+}
+
+// Line segments projected by a curved projector are stroked as curves, without streaks across discontinuities.
+// perspectiveProjector wraps a Projector, declaring whether it is perspective.
+type perspectiveProjector struct {
+	graphix.Projector
+	perspective bool
+}
+
+func (pr *perspectiveProjector) Perspective() bool { return pr.perspective }
+
+// Line segments crossing the near z-clip plane of a perspective projector are clipped in view space.
+func TestZRasterRunPerspectiveZClip(t *testing.T) {
+	paths := []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(1, 0, -1), Color: color.White}},
+		End:       graphix.NewVec3(1, 0, 1),
+		LineWidth: 3,
+	}}
+	isLit := func(perspective bool, x int) bool {
+		cam := graphix.NewCamera(
+			graphix.IdentityTransform(),
+			&perspectiveProjector{Projector: graphix.NewPerspective(1), perspective: perspective},
+			graphix.NewScreen(100, 100, -10, -10, 10, 10),
+		)
+		r, _, _, _ := Run(Settings{Camera: cam, Paths: paths, Workers: 1}).At(x, 50).RGBA()
+		return r > 0
+	}
+	// The segment is clipped at (1,0,-.2), which is projected to x=5.
+	assert.True(t, isLit(true, 70))
+	// Interpolating the projection of the point behind the camera, whose x=-1, gives x=.2 instead.
+	assert.False(t, isLit(false, 70))
+	assert.True(t, isLit(false, 52))
+}
+
+// Geometry around the pivot of an axonometric projector is in front of the camera.
+func TestZRasterRunAxonometric(t *testing.T) {
+	sc := graphix.NewScreen(100, 100, -2, -2, 2, 2)
+	cam := graphix.NewCamera(graphix.NewOrtho2DCamera(sc).ViewTransform(), graphix.NewIsometric(1), sc)
+	paths := []*SpacePath{
+		newTestPath(graphix.NewVec3(0, 0, 0), graphix.NewVec3(1.5, 0, 0)),
+		newTestPath(graphix.NewVec3(0, 0, 0), graphix.NewVec3(0, 1.5, 0)),
+	}
+	img := Run(Settings{Camera: cam, Paths: paths, Workers: 1})
+	isLit := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r > 0
+	}
+	// The world origin is at the center of the screen, +x points down-right and +y points up.
+	assert.True(t, isLit(50, 50))
+	assert.True(t, isLit(73, 63))
+	assert.True(t, isLit(50, 22))
+	assert.False(t, isLit(27, 63))
+}
+
+func TestZRasterRunCurvedProjector(t *testing.T) {
+	cam := graphix.NewCamera(
+		graphix.NewViewTransform(graphix.NewVec3(0, 0, 0), graphix.NewVec3(0, 0, -1), graphix.NewVec3(0, 1, 0)),
+		graphix.NewEquirectangular(.1, 100),
+		graphix.NewFOVScreen(400, 200),
+	)
+	color := color.NRGBA{R: 0, G: 0, B: 0xff, A: 0xff}
+	project := func(pos *graphix.Vec3) *graphix.Projection {
+		p := cam.Projector().Project(graphix.BlankProjection(), cam.ViewTransform().Apply(graphix.BlankVec3(), pos))
+		return cam.Screen().Map(p, p)
+	}
+	isBlack := func(img image.Image, p *graphix.Projection) bool {
+		r, g, b, _ := img.At(int(p[0]), int(p[1])).RGBA()
+		return r == 0 && g == 0 && b == 0
+	}
+
+	paths := []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(-5, 1, -1), Color: color}},
+		End:       graphix.NewVec3(5, 1, -1),
+		LineWidth: 2,
+	}}
+	img := Run(Settings{Camera: cam, Paths: paths, Workers: 1})
+	// The curve passes through the projected midpoint, rather than the chord between the projected endpoints.
+	assert.False(t, isBlack(img, project(graphix.NewVec3(0, 1, -1))))
+	assert.False(t, isBlack(img, project(graphix.NewVec3(2, 1, -1))))
+	p1, p2 := project(graphix.NewVec3(-5, 1, -1)), project(graphix.NewVec3(5, 1, -1))
+	assert.True(t, isBlack(img, graphix.NewProjection((p1[0]+p2[0])/2, (p1[1]+p2[1])/2, 0)))
+
+	// Crossing the seam behind the camera.
+	paths = []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(1, 0, 5), Color: color}},
+		End:       graphix.NewVec3(-1, 0, 5),
+		LineWidth: 2,
+	}}
+	img = Run(Settings{Camera: cam, Paths: paths, Workers: 1})
+	assert.False(t, isBlack(img, project(graphix.NewVec3(1, 0, 5))))
+	assert.False(t, isBlack(img, project(graphix.NewVec3(-1, 0, 5))))
+	assert.True(t, isBlack(img, graphix.NewProjection(200, 100, 0)))
+}
+
+func TestComposeStereo(t *testing.T) {
+	left := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	right := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	left.Set(0, 0, color.RGBA{R: 10, G: 20, B: 30, A: 0xff})
+	left.Set(1, 0, color.RGBA{R: 40, G: 50, B: 60, A: 0xff})
+	right.Set(0, 0, color.RGBA{R: 70, G: 80, B: 90, A: 0xff})
+	right.Set(1, 0, color.RGBA{R: 100, G: 110, B: 120, A: 0xff})
+
+	img := ComposeStereo(left, right, AnaglyphRedCyan)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), img.Bounds())
+	assert.Equal(t, color.RGBA{R: 10, G: 80, B: 90, A: 0xff}, img.At(0, 0))
+	assert.Equal(t, color.RGBA{R: 40, G: 110, B: 120, A: 0xff}, img.At(1, 0))
+
+	img = ComposeStereo(left, right, SideBySide)
+	assert.Equal(t, image.Rect(0, 0, 4, 1), img.Bounds())
+	assert.Equal(t, left.At(1, 0), img.At(1, 0))
+	assert.Equal(t, right.At(0, 0), img.At(2, 0))
+
+	img = ComposeStereo(left, right, OverUnder)
+	assert.Equal(t, image.Rect(0, 0, 2, 2), img.Bounds())
+	assert.Equal(t, left.At(1, 0), img.At(1, 0))
+	assert.Equal(t, right.At(1, 0), img.At(1, 1))
+}
+
+func TestZRasterRunStereo(t *testing.T) {
+	paths := []*SpacePath{{
+		Segments:  []*SpaceVertex{{Pos: graphix.NewVec3(-1, -1, -4), Color: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}}},
+		End:       graphix.NewVec3(1, 1, -4),
+		LineWidth: 3,
+	}}
+	cam := graphix.NewCamera(
+		graphix.NewViewTransform(graphix.NewVec3(0, 0, 8), graphix.NewVec3(0, 0, -1), graphix.NewVec3(0, 1, 0)),
+		graphix.NewPerspective(4),
+		graphix.NewScreen(100, 100, -2, -2, 2, 2),
+	)
+	settings := Settings{Camera: cam, Paths: paths, Workers: 1}
+	img := RunStereo(settings, StereoSettings{Interocular: .5, Convergence: 8, Layout: SideBySide})
+	assert.Equal(t, image.Rect(0, 0, 200, 100), img.Bounds())
+
+	// The path is behind the convergence plane, so the right eye sees it further right than the left eye.
+	rig := graphix.NewStereoRig(cam, .5, 8)
+	settings.Camera = rig.Left()
+	left := Run(settings)
+	settings.Camera = rig.Right()
+	right := Run(settings)
+	assert.Equal(t, ComposeStereo(left, right, SideBySide), img)
+	firstLit := func(img image.Image, x0 int) int {
+		for x := x0; x < x0+100; x++ {
+			if r, _, _, _ := img.At(x, 50).RGBA(); r > 0 {
+				return x - x0
+			}
+		}
+		return -1
+	}
+	assert.Greater(t, firstLit(img, 100), firstLit(img, 0))
+}
+
+func TestRunStereoValidation(t *testing.T) {
+	settings := Settings{Camera: newTestCamera(20, 20), Workers: 1}
+	for _, c := range []struct {
+		stereo StereoSettings
+		err    string
+	}{
+		{StereoSettings{Interocular: .5}, "convergence must be positive, got 0"},
+		{StereoSettings{Interocular: .5, Convergence: math.NaN()}, "convergence must be positive, got NaN"},
+		{StereoSettings{Interocular: -.5, Convergence: 8}, "interocular distance must be non-negative and finite, got -0.5"},
+		{StereoSettings{Interocular: math.Inf(1), Convergence: 8}, "interocular distance must be non-negative and finite"},
+		{StereoSettings{Interocular: .5, Convergence: 8, Layout: OverUnder + 1}, "unknown stereo layout 3"},
+	} {
+		_, err := RunStereoContext(context.Background(), settings, c.stereo)
+		assert.ErrorContains(t, err, c.err)
+	}
+	_, err := RunStereoContext(context.Background(), settings, StereoSettings{Interocular: .5, Convergence: math.Inf(1)})
+	assert.NoError(t, err)
+
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	assert.PanicsWithError(t, "unknown stereo layout -1", func() { ComposeStereo(img, img, -1) })
+}
+
+func newTestCamera(width, height int) *graphix.Camera {
+	return graphix.NewCamera(
+		graphix.NewViewTransform(graphix.NewVec3(0, 0, 8), graphix.NewVec3(0, 0, -1), graphix.NewVec3(0, 1, 0)),
+		graphix.NewOrthographic(),
+		graphix.NewScreen(width, height, -6, -6, 6, 6),
+	)
+}
+
+// Returns a camera at (0,0,z) facing -z with a field of view of π/2.
+func newFOVPerspectiveTestCamera(t *testing.T, z, near, far float64, width, height int) *graphix.Camera {
+	cam, err := graphix.NewFOVPerspectiveCamera(
+		graphix.NewViewTransform(graphix.NewVec3(0, 0, z), graphix.NewVec3(0, 0, -1), graphix.NewVec3(0, 1, 0)),
+		math.Pi/2,
+		near,
+		far,
+		width,
+		height,
+	)
+	assert.NoError(t, err)
+	return cam
+}
+
+func newTestPath(from, to *graphix.Vec3) *SpacePath {
+	return &SpacePath{
+		Segments:  []*SpaceVertex{{Pos: from, Color: color.NRGBA{R: 0xff, A: 0xff}}},
+		End:       to,
+		LineWidth: 3,
+	}
+}
+
+func TestRunContextValidation(t *testing.T) {
+	valid := func() Settings {
+		return Settings{
+			Camera:  newTestCamera(20, 20),
+			Paths:   []*SpacePath{newTestPath(graphix.NewVec3(-1, 0, 0), graphix.NewVec3(1, 0, 0))},
+			Points:  []*SpacePoint{{Pos: graphix.NewVec3(0, 1, 0), Color: color.White, Size: 3}},
+			Workers: 2,
+		}
+	}
+	img, err := RunContext(context.Background(), valid())
+	assert.NoError(t, err)
+	assert.Equal(t, Run(valid()), img)
+
+	cases := []struct {
+		modify func(s *Settings)
+		err    string
+	}{
+		{func(s *Settings) { s.Camera = nil }, "camera must not be nil"},
+		{func(s *Settings) { s.Camera = graphix.NewCamera(nil, graphix.NewOrthographic(), nil) }, "camera must have"},
+		{func(s *Settings) { s.Camera = newTestCamera(0, 20) }, "screen dimension must be positive"},
+		{func(s *Settings) { s.Workers = 0 }, "workers must be positive"},
+		{func(s *Settings) { s.TileSize = -1 }, "tile size must not be negative"},
+		{func(s *Settings) { s.Alpha = 2 }, "unknown alpha mode 2"},
+		{func(s *Settings) { s.Format = 3 }, "unknown output format 3"},
+		{func(s *Settings) { s.DepthCue = &DepthCue{Near: 2, Far: 1} }, "finite near < far"},
+		{func(s *Settings) { s.DepthCue = &DepthCue{Far: math.Inf(1)} }, "finite near < far"},
+		{func(s *Settings) { s.DepthCue = &DepthCue{Far: 1, Mode: ExponentialFog} }, "positive density"},
+		{func(s *Settings) { s.DepthCue = &DepthCue{Far: 1, Mode: CustomFog} }, "must have a function"},
+		{func(s *Settings) { s.DepthCue = &DepthCue{Far: 1, Mode: 3} }, "unknown fog mode 3"},
+		{func(s *Settings) { s.Supersampling = -1 }, "supersampling must not be negative"},
+		{func(s *Settings) { s.Supersampling = 20000 }, "is too large"},
+		{func(s *Settings) { s.Filter = -1 }, "unknown filter -1"},
+		{func(s *Settings) { s.Paths = append(s.Paths, nil) }, "path 1 must not be nil"},
+		{func(s *Settings) { s.Paths[0].LineWidth = math.NaN() }, "invalid line width"},
+		{func(s *Settings) { s.Paths[0].EndWidth = -1 }, "invalid end width -1"},
+		{func(s *Settings) { s.Paths[0].Segments[0].Width = math.Inf(1) }, "vertex 0 has invalid width +Inf"},
+		{func(s *Settings) { s.Paths[0].Segments[0].Pos[1] = math.Inf(-1) }, "non-finite position"},
+		{func(s *Settings) { s.Paths[0].Segments[0].Color = nil }, "must have a position and a color"},
+		{func(s *Settings) { s.Paths[0].End = nil }, "must have an end"},
+		{func(s *Settings) { s.Paths[0].End[2] = math.NaN() }, "non-finite end"},
+		{func(s *Settings) { s.Points = append(s.Points, nil) }, "point 1 must not be nil"},
+		{func(s *Settings) { s.Points[0].Color = nil }, "point 0 must have a position and a color"},
+		{func(s *Settings) { s.Points[0].Pos[1] = math.NaN() }, "point 0 has non-finite position"},
+		{func(s *Settings) { s.Points[0].Size = -1 }, "point 0 has invalid size -1"},
+		{func(s *Settings) { s.Points[0].Shape = 5 }, "point 0: unknown point shape 5"},
+	}
+	for _, c := range cases {
+		s := valid()
+		c.modify(&s)
+		_, err := RunContext(context.Background(), s)
+		assert.ErrorContains(t, err, c.err)
+		assert.PanicsWithValue(t, "failed to rasterize: "+err.Error(), func() { Run(s) })
+	}
+}
+
+// A projector panicking on points left of the camera.
+type panickingProjector struct {
+	graphix.Projector
+}
+
+func (pp *panickingProjector) Project(p *graphix.Projection, v *graphix.Vec3) *graphix.Projection {
+	if v[0] < 0 {
+		panic("left of the camera")
+	}
+	return pp.Projector.Project(p, v)
+}
+
+func TestRunContextErrors(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	var paths []*SpacePath
+	for i := range 100 {
+		y := float64(i)/20 - 2.5
+		paths = append(paths, newTestPath(graphix.NewVec3(-5, y, 0), graphix.NewVec3(5, y, 0)))
+	}
+	settings := Settings{Camera: newTestCamera(100, 100), Paths: paths, Workers: 4}
+
+	// Canceled rendering.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RunContext(ctx, settings)
+	assert.ErrorIs(t, err, context.Canceled)
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, err = RunContext(ctx, settings)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	settings.TileSize = 16
+	_, err = RunContext(ctx, settings)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	settings.TileSize = 0
+
+	// A panicking worker fails the rendering and stops the others.
+	settings.Camera = graphix.NewCamera(
+		settings.Camera.ViewTransform(),
+		&panickingProjector{settings.Camera.Projector()},
+		settings.Camera.Screen(),
+	)
+	_, err = RunContext(context.Background(), settings)
+	assert.ErrorContains(t, err, "panicked: left of the camera")
+	settings.TileSize = 16
+	_, err = RunContext(context.Background(), settings)
+	assert.ErrorContains(t, err, "panicked: left of the camera")
+
+	_, err = RunStereoContext(context.Background(), Settings{Workers: 1}, StereoSettings{})
+	assert.ErrorContains(t, err, "camera must not be nil")
+
+	// All workers have exited.
+	assert.Equal(t, goroutines, runtime.NumGoroutine())
+}
+
+func TestGroupByPixel(t *testing.T) {
+	frag := func(pixel int32, z float32) fragment { return fragment{pixel: pixel, zColor: zColor{z: z}} }
+	fbp, err := groupByPixel([][]fragment{
+		{frag(2, 1), frag(0, 2), frag(2, 3)},
+		nil,
+		{frag(3, 4), frag(2, 5)},
+	}, 4)
+	assert.NoError(t, err)
+	defer fbp.release()
+	zs := func(i int) []float32 {
+		var zs []float32
+		for _, zc := range fbp.at(i) {
+			zs = append(zs, zc.z)
+		}
+		return zs
+	}
+	assert.Equal(t, []float32{2}, zs(0))
+	assert.Empty(t, zs(1))
+	// Fragments of the same pixel keep the worker order and then the recording order.
+	assert.Equal(t, []float32{1, 3, 5}, zs(2))
+	assert.Equal(t, []float32{4}, zs(3))
+}
+
+func TestRunReusesFragmentArenas(t *testing.T) {
+	// Consecutive renders with pooled arenas must not leak fragments into each other.
+	settings := Settings{
+		Camera:  newTestCamera(40, 40),
+		Paths:   []*SpacePath{newTestPath(graphix.NewVec3(-1, -1, 0), graphix.NewVec3(1, 1, 0))},
+		Workers: 3,
+	}
+	first := Run(settings)
+	settings.Paths = []*SpacePath{newTestPath(graphix.NewVec3(-1, 1, 0), graphix.NewVec3(1, -1, 0))}
+	Run(settings)
+	settings.Paths = []*SpacePath{newTestPath(graphix.NewVec3(-1, -1, 0), graphix.NewVec3(1, 1, 0))}
+	assert.Equal(t, first, Run(settings))
+}
+
+func TestRunTiled(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randVec3 := func(r float64) *graphix.Vec3 {
+		return graphix.NewVec3(r*(2*rng.Float64()-1), r*(2*rng.Float64()-1), r*(2*rng.Float64()-1))
+	}
+	var paths []*SpacePath
+	for range 50 {
+		var segments []*SpaceVertex
+		pos := randVec3(5)
+		for range 1 + rng.IntN(20) {
+			sv := &SpaceVertex{
+				Pos:   pos,
+				Color: color.NRGBA{R: uint8(rng.IntN(256)), G: uint8(rng.IntN(256)), B: 0xff, A: uint8(rng.IntN(256))},
+			}
+			// Tapered strokes for some of the vertices.
+			if rng.IntN(2) == 0 {
+				sv.Width = 15 * rng.Float64()
+			}
+			segments = append(segments, sv)
+			pos = graphix.BlankVec3().Add(pos, randVec3(2))
+		}
+		paths = append(paths, &SpacePath{Segments: segments, End: pos, LineWidth: 10 * rng.Float64()})
+	}
+	paths = append(paths, &SpacePath{})
+
+	for _, cam := range []*graphix.Camera{
+		newFOVPerspectiveTestCamera(t, 6, .5, 10, 203, 151),
+		graphix.NewCamera(
+			graphix.NewViewTransform(graphix.NewVec3(0, 0, 0), graphix.NewVec3(0, 0, -1), graphix.NewVec3(0, 1, 0)),
+			graphix.NewEquirectangular(.1, 100),
+			graphix.NewFOVScreen(240, 120),
+		),
+	} {
+		exp := Run(Settings{Camera: cam, Paths: paths, Workers: 1})
+		for _, ts := range []int{7, 16, 50, 300} {
+			for _, workers := range []int{1, 4} {
+				act := Run(Settings{Camera: cam, Paths: paths, Workers: workers, TileSize: ts})
+				assert.Equal(t, exp, act, "tile size %v, workers %v", ts, workers)
+			}
+		}
+	}
+}
+
+func TestRunBackground(t *testing.T) {
+	// A half transparent red line across the middle row of the screen.
+	path := newTestPath(graphix.NewVec3(-7, 0, 0), graphix.NewVec3(7, 0, 0))
+	path.Segments[0].Color = color.NRGBA{R: 0xff, A: 0x80}
+	settings := Settings{Camera: newTestCamera(20, 20), Paths: []*SpacePath{path}, Workers: 2}
+
+	// Opaque black by default.
+	img := Run(settings)
+	assert.Equal(t, color.RGBA{A: 0xff}, img.At(10, 0))
+	assert.Equal(t, color.RGBA{R: 0x80, A: 0xff}, img.At(10, 10))
+
+	settings.Background = image.NewUniform(color.White)
+	img = Run(settings)
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, img.At(10, 0))
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0x7f, B: 0x7f, A: 0xff}, img.At(10, 10))
+
+	// The background image is aligned with the screen regardless of its bounds.
+	bg := image.NewRGBA(image.Rect(5, 5, 25, 25))
+	bg.Set(5, 5, color.RGBA{B: 0xff, A: 0xff})
+	settings.Background = bg
+	img = Run(settings)
+	assert.Equal(t, color.RGBA{B: 0xff, A: 0xff}, img.At(0, 0))
+	assert.Equal(t, color.RGBA{}, img.At(1, 0))
+	assert.Equal(t, color.RGBA{R: 0x80, A: 0x80}, img.At(10, 10))
+
+	settings.Background = image.Transparent
+	for _, ts := range []int{0, 8} {
+		settings.TileSize = ts
+		settings.Alpha = PremultipliedAlpha
+		img = Run(settings)
+		assert.IsType(t, &image.RGBA{}, img)
+		assert.Equal(t, color.RGBA{}, img.At(10, 0))
+		assert.Equal(t, color.RGBA{R: 0x80, A: 0x80}, img.At(10, 10))
+		settings.Alpha = StraightAlpha
+		img = Run(settings)
+		assert.IsType(t, &image.NRGBA{}, img)
+		assert.Equal(t, color.NRGBA{}, img.At(10, 0))
+		assert.Equal(t, color.NRGBA{R: 0xff, A: 0x80}, img.At(10, 10))
+	}
+
+	// Stereo images are composed before the alpha conversion.
+	settings.TileSize = 0
+	img = RunStereo(settings, StereoSettings{Convergence: 8, Layout: SideBySide})
+	assert.IsType(t, &image.NRGBA{}, img)
+	assert.Equal(t, color.NRGBA{R: 0xff, A: 0x80}, img.At(30, 10))
+}
+
+func TestRunSupersampled(t *testing.T) {
+	var paths []*SpacePath
+	for i := range 5 {
+		y := float64(i) - 2
+		path := newTestPath(graphix.NewVec3(-5, y, float64(i)), graphix.NewVec3(5, -y, float64(-i)))
+		path.Segments[0].Color = color.NRGBA{R: uint8(50 * i), G: 0xff, A: 0xc0}
+		paths = append(paths, path)
+	}
+	settings := Settings{Camera: newTestCamera(41, 37), Paths: paths, Workers: 1, Supersampling: 2}
+
+	// With the box filter, each pixel is the average of its subsamples rendered over the background.
+	sub := Run(Settings{
+		Camera: graphix.NewCamera(
+			settings.Camera.ViewTransform(),
+			settings.Camera.Projector(),
+			graphix.NewScaledScreen(settings.Camera.Screen(), 2),
+		),
+		Paths: func() []*SpacePath {
+			var scaled []*SpacePath
+			for _, path := range paths {
+				s := *path
+				s.LineWidth *= 2
+				scaled = append(scaled, &s)
+			}
+			return scaled
+		}(),
+		Workers: 1,
+	}).(*image.RGBA)
+	img := Run(settings).(*image.RGBA)
+	for y := range 37 {
+		for x := range 41 {
+			for c := range 4 {
+				sum := 0
+				for _, d := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+					sum += int(sub.Pix[(2*y+d[1])*sub.Stride+4*(2*x+d[0])+c])
+				}
+				assert.InDelta(t, float64(sum)/4, float64(img.Pix[y*img.Stride+4*x+c]), 1)
+			}
+		}
+	}
+
+	for _, filter := range []Filter{BoxFilter, TentFilter, LanczosFilter} {
+		settings.Filter, settings.TileSize, settings.Workers = filter, 0, 1
+		settings.Background = image.Transparent
+		img := Run(settings).(*image.RGBA)
+		// Colors stay premultiplied despite the negative lobes of the Lanczos filter.
+		for i := 0; i < len(img.Pix); i += 4 {
+			assert.LessOrEqual(t, img.Pix[i], img.Pix[i+3])
+			assert.LessOrEqual(t, img.Pix[i+1], img.Pix[i+3])
+			assert.LessOrEqual(t, img.Pix[i+2], img.Pix[i+3])
+		}
+		assert.Equal(t, color.RGBA{}, img.At(0, 0))
+		// The tiled render is the same as the one of a single worker.
+		settings.TileSize, settings.Workers = 8, 3
+		assert.Equal(t, img, Run(settings))
+	}
+
+	// A wide line keeps its color in the interior.
+	settings.Paths = []*SpacePath{newTestPath(graphix.NewVec3(-7, 0, 0), graphix.NewVec3(7, 0, 0))}
+	settings.Paths[0].LineWidth = 15
+	for _, filter := range []Filter{BoxFilter, TentFilter, LanczosFilter} {
+		settings.Filter = filter
+		assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, Run(settings).At(20, 18))
+	}
+}
+
+func TestRunOutputFormats(t *testing.T) {
+	// Many faint lines stacked over the middle row of the screen.
+	var paths []*SpacePath
+	for i := range 200 {
+		path := newTestPath(graphix.NewVec3(-7, 0, float64(i)/100), graphix.NewVec3(7, 0, float64(i)/100))
+		path.Segments[0].Color = color.NRGBA{R: 0x40, A: 2}
+		paths = append(paths, path)
+	}
+	settings := Settings{Camera: newTestCamera(20, 20), Paths: paths, Workers: 2}
+	expA := 1 - math.Pow(1-2./255, 200)
+	exp := expA * 0x40 / 0xff
+	red := func(img image.Image) float64 {
+		r, _, _, _ := img.At(10, 10).RGBA()
+		return float64(r) / math.MaxUint16
+	}
+
+	// Truncation at each layer loses the faint lines entirely with 8 bits.
+	img := Run(settings)
+	assert.IsType(t, &image.RGBA{}, img)
+	assert.Zero(t, red(img))
+
+	settings.Format = RGBA16
+	img = Run(settings)
+	assert.IsType(t, &image.RGBA64{}, img)
+	assert.InDelta(t, exp, red(img), 1e-3)
+	settings.TileSize = 8
+	assert.Equal(t, img, Run(settings))
+	settings.Alpha = StraightAlpha
+	assert.IsType(t, &image.NRGBA64{}, Run(settings))
+
+	settings.Format, settings.Alpha, settings.TileSize = Float32, PremultipliedAlpha, 0
+	img = Run(settings)
+	fi := img.(*graphix.FloatImage)
+	assert.False(t, fi.Straight)
+	i := fi.PixOffset(10, 10)
+	assert.InDelta(t, exp, fi.Pix[i], 1e-4)
+	assert.Equal(t, []float32{0, 0, 1}, fi.Pix[i+1:i+4])
+	assert.Equal(t, []float32{0, 0, 0, 1}, fi.Pix[:4])
+
+	// Transparent float output with straight alpha.
+	settings.Background, settings.Alpha = image.Transparent, StraightAlpha
+	fi = Run(settings).(*graphix.FloatImage)
+	assert.True(t, fi.Straight)
+	assert.InDelta(t, 0x40/255., fi.Pix[i], 1e-4)
+	assert.InDelta(t, expA, fi.Pix[i+3], 1e-4)
+	assert.Equal(t, []float32{0, 0, 0, 0}, fi.Pix[:4])
+
+	// Supersampling and stereo rendering keep the format.
+	settings.Alpha, settings.Supersampling = PremultipliedAlpha, 2
+	fi = Run(settings).(*graphix.FloatImage)
+	assert.InDelta(t, exp, fi.Pix[i], 1e-4)
+	settings.Format = RGBA16
+	assert.IsType(t, &image.RGBA64{}, Run(settings))
+	assert.IsType(t, &image.RGBA64{}, RunStereo(settings, StereoSettings{Convergence: 8, Layout: SideBySide}))
+	settings.Format, settings.Supersampling = Float32, 0
+	assert.IsType(t, &graphix.FloatImage{}, RunStereo(settings, StereoSettings{Convergence: 8}))
+}
+
+func TestRunDepthCue(t *testing.T) {
+	// Red lines at z-distances 4, 6, 10 and 16 from the camera.
+	var paths []*SpacePath
+	for i, z := range []float64{4, 2, -2, -8} {
+		y := float64(i)*2 - 3
+		paths = append(paths, newTestPath(graphix.NewVec3(-7, y, z), graphix.NewVec3(7, y, z)))
+	}
+	settings := Settings{
+		Camera:   newTestCamera(24, 24),
+		Paths:    paths,
+		Workers:  2,
+		Format:   Float32,
+		DepthCue: &DepthCue{Near: 5, Far: 15},
+	}
+	// Returns the colors of the middle of the lines.
+	colors := func() [][]float32 {
+		fi := Run(settings).(*graphix.FloatImage)
+		var cs [][]float32
+		for i := range paths {
+			o := fi.PixOffset(12, 18-4*i)
+			cs = append(cs, fi.Pix[o:o+4])
+		}
+		return cs
+	}
+	assertColors := func(exp [][4]float64) {
+		for i, c := range colors() {
+			assert.InDeltaSlice(t, exp[i][:], c, 1e-4, "line %v", i)
+		}
+	}
+
+	// Fading out over the opaque black background.
+	assertColors([][4]float64{{1, 0, 0, 1}, {.9, 0, 0, 1}, {.5, 0, 0, 1}, {0, 0, 0, 1}})
+	settings.Background = image.Transparent
+	assertColors([][4]float64{{1, 0, 0, 1}, {.9, 0, 0, .9}, {.5, 0, 0, .5}, {0, 0, 0, 0}})
+
+	// Toward a fog color.
+	settings.DepthCue.Color = color.NRGBA{B: 0xff, A: 0xff}
+	assertColors([][4]float64{{1, 0, 0, 1}, {.9, 0, .1, 1}, {.5, 0, .5, 1}, {0, 0, 1, 1}})
+
+	settings.DepthCue.Mode, settings.DepthCue.Density = ExponentialFog, 2
+	f := func(t float64) float64 { return 1 - math.Exp(-2*t) }
+	assertColors([][4]float64{{1, 0, 0, 1}, {1 - f(.1), 0, f(.1), 1}, {1 - f(.5), 0, f(.5), 1}, {1 - f(1), 0, f(1), 1}})
+
+	settings.DepthCue.Mode, settings.DepthCue.Func = CustomFog, func(t float64) float64 { return 2 * t }
+	assertColors([][4]float64{{1, 0, 0, 1}, {.8, 0, .2, 1}, {0, 0, 1, 1}, {0, 0, 1, 1}})
+}
+
+func TestRunPerVertexWidth(t *testing.T) {
+	// A horizontal line across the screen widening from 2 to 20 pixels, with the line width of the path in the middle.
+	path := newTestPath(graphix.NewVec3(-5, 0, 0), graphix.NewVec3(5, 0, 0))
+	path.Segments[0].Width = 2
+	path.Segments = append(path.Segments, &SpaceVertex{Pos: graphix.NewVec3(0, 0, 0), Color: color.NRGBA{R: 0xff, A: 0xff}})
+	path.LineWidth, path.EndWidth = 11, 20
+	settings := Settings{Camera: newTestCamera(48, 48), Paths: []*SpacePath{path}, Workers: 1}
+
+	// Returns the line width in column x as its total red coverage.
+	height := func(img image.Image, x int) float64 {
+		h := 0.
+		for y := range 48 {
+			r, _, _, _ := img.At(x, y).RGBA()
+			h += float64(r) / 0xffff
+		}
+		return h
+	}
+	img := Run(settings)
+	prev := 0.
+	for x := 6; x < 42; x++ {
+		h := height(img, x)
+		assert.GreaterOrEqual(t, h, prev, "column %v", x)
+		// Line width interpolated at the center of the column.
+		w := 2 + 18*(float64(x)+.5-4)/40
+		assert.InDelta(t, w, h, .5, "column %v", x)
+		prev = h
+	}
+	// The two segments join without seams: the half transparent line is not doubled where they overlap.
+	path.Segments[0].Color = color.NRGBA{R: 0xff, A: 0x80}
+	path.Segments[1].Color = path.Segments[0].Color
+	img = Run(settings)
+	for x := 6; x < 42; x++ {
+		assert.Equal(t, img.At(x, 24), img.At(6, 24), "column %v", x)
+	}
+
+	// Tiled rendering is the same as the one of a single worker.
+	settings.TileSize, settings.Workers = 8, 3
+	assert.Equal(t, img, Run(settings))
+
+	// Supersampling scales the line widths.
+	path.Segments[0].Color, path.Segments[1].Color = color.NRGBA{R: 0xff, A: 0xff}, color.NRGBA{R: 0xff, A: 0xff}
+	settings.Supersampling = 3
+	img = Run(settings)
+	for x := 6; x < 42; x++ {
+		w := 2 + 18*(float64(x)+.5-4)/40
+		assert.InDelta(t, w, height(img, x), .5, "column %v", x)
+	}
+}
+
+func TestRunSmoothColors(t *testing.T) {
+	// A horizontal line across the screen from red through a half transparent green vertex to blue.
+	path := newTestPath(graphix.NewVec3(-5, 0, 0), graphix.NewVec3(5, 0, 0))
+	path.Segments = append(path.Segments, &SpaceVertex{Pos: graphix.NewVec3(0, 0, 0), Color: color.NRGBA{G: 0xff, A: 0x80}})
+	path.EndColor = color.NRGBA{B: 0xff, A: 0xff}
+	settings := Settings{
+		Camera:     newTestCamera(48, 48),
+		Paths:      []*SpacePath{path},
+		Workers:    1,
+		Background: image.Transparent,
+		Format:     Float32,
+	}
+	// Returns the color of column x on the line.
+	colorAt := func(fi *graphix.FloatImage, x int) []float32 {
+		o := fi.PixOffset(x, 24)
+		return fi.Pix[o : o+4]
+	}
+
+	// Each segment is stroked with the color of its start vertex by default.
+	fi := Run(settings).(*graphix.FloatImage)
+	for x := 6; x < 42; x++ {
+		exp := []float64{1, 0, 0, 1}
+		if x == 24 {
+			// Covered by both segments, where the opaque one is kept.
+			continue
+		}
+		if x > 24 {
+			exp = []float64{0, 128. / 255, 0, 128. / 255}
+		}
+		assert.InDeltaSlice(t, exp, colorAt(fi, x), 1e-4, "column %v", x)
+	}
+
+	// The premultiplied colors are interpolated like the z-distance, at the top-left corner of each pixel.
+	settings.SmoothColors = true
+	fi = Run(settings).(*graphix.FloatImage)
+	for x := 6; x < 42; x++ {
+		var exp []float64
+		if s := float64(x-4) / 20; s < 1 {
+			exp = []float64{1 - s, s * 128 / 255, 0, 1 - s + s*128/255}
+		} else {
+			s--
+			exp = []float64{0, (1 - s) * 128 / 255, s, (1-s)*128/255 + s}
+		}
+		assert.InDeltaSlice(t, exp, colorAt(fi, x), 1e-3, "column %v", x)
+	}
+
+	// The end color defaults to the color of the last vertex.
+	path.EndColor = nil
+	fi = Run(settings).(*graphix.FloatImage)
+	assert.InDeltaSlice(t, []float64{0, 128. / 255, 0, 128. / 255}, colorAt(fi, 40), 1e-4)
+
+	// Tiled rendering is the same as the one of a single worker.
+	path.EndColor = color.NRGBA{B: 0xff, A: 0xff}
+	fi = Run(settings).(*graphix.FloatImage)
+	settings.TileSize, settings.Workers = 8, 3
+	assert.Equal(t, fi, Run(settings))
+}
+
+func TestRunPoints(t *testing.T) {
+	settings := Settings{
+		Camera:     newTestCamera(48, 48),
+		Workers:    2,
+		Background: image.Transparent,
+		Format:     Float32,
+	}
+	// Returns the total alpha of the image, i.e., the area covered in pixels.
+	area := func(fi *graphix.FloatImage) float64 {
+		sum := 0.
+		for i := 3; i < len(fi.Pix); i += 4 {
+			sum += float64(fi.Pix[i])
+		}
+		return sum
+	}
+	red := color.NRGBA{R: 0xff, A: 0xff}
+
+	// The shapes of 20 pixels, or 5 world units which is 20 pixels on the screen.
+	for _, c := range []struct {
+		shape PointShape
+		area  float64
+	}{
+		{DiscPoint, math.Pi * 100},
+		{SpherePoint, math.Pi * 100},
+		{CrossMarker, 2*20*5 - 5*5},
+		{SquareMarker, 20 * 20},
+		{TriangleMarker, 3 * math.Sqrt(3) / 4 * 100},
+	} {
+		for _, pt := range []*SpacePoint{
+			{Pos: graphix.NewVec3(0, 0, 0), Color: red, Shape: c.shape, Size: 20},
+			{Pos: graphix.NewVec3(0, 0, 0), Color: red, Shape: c.shape, Size: 5, WorldSize: true},
+		} {
+			settings.Points = []*SpacePoint{pt}
+			assert.InEpsilon(t, c.area, area(Run(settings).(*graphix.FloatImage)), .01, "shape %v", c.shape)
+		}
+	}
+
+	// The sphere is lit from the upper left.
+	settings.Points[0].Shape = SpherePoint
+	fi := Run(settings).(*graphix.FloatImage)
+	assert.Greater(t, fi.Pix[fi.PixOffset(20, 20)], fi.Pix[fi.PixOffset(24, 24)])
+	assert.Greater(t, fi.Pix[fi.PixOffset(24, 24)], fi.Pix[fi.PixOffset(28, 28)])
+	assert.Equal(t, float32(1), fi.Pix[fi.PixOffset(28, 28)+3])
+
+	// With a perspective camera, a point of world size is smaller when it is farther.
+	perspective := settings
+	perspective.Camera = newFOVPerspectiveTestCamera(t, 8, 1, 100, 48, 48)
+	var areas []float64
+	for _, z := range []float64{4, 0, -8} {
+		perspective.Points = []*SpacePoint{{Pos: graphix.NewVec3(0, 0, z), Color: red, Size: 2, WorldSize: true}}
+		areas = append(areas, area(Run(perspective).(*graphix.FloatImage)))
+	}
+	// The radius in pixels is 24/d at distance d.
+	assert.InEpsilon(t, math.Pi*36, areas[0], .02)
+	assert.InEpsilon(t, math.Pi*9, areas[1], .02)
+	assert.InEpsilon(t, math.Pi*2.25, areas[2], .05)
+	// Points outside of the z-clip planes are not drawn.
+	perspective.Points[0].Pos[2] = 7.5
+	assert.Zero(t, area(Run(perspective).(*graphix.FloatImage)))
+
+	// Points are depth sorted with the paths: a green line through a blue sphere of radius 2, 1.5 in front of its
+	// center, is hidden by the sphere in the middle, but not where the sphere is farther than the line.
+	path := newTestPath(graphix.NewVec3(-5, 0, 1.5), graphix.NewVec3(5, 0, 1.5))
+	path.Segments[0].Color = color.NRGBA{G: 0xff, A: 0xff}
+	settings.Paths = []*SpacePath{path}
+	settings.Points = []*SpacePoint{
+		{Pos: graphix.NewVec3(0, 0, 0), Color: color.NRGBA{B: 0xff, A: 0xff}, Shape: SpherePoint, Size: 4, WorldSize: true},
+	}
+	fi = Run(settings).(*graphix.FloatImage)
+	o := fi.PixOffset(24, 24)
+	assert.Zero(t, fi.Pix[o+1])
+	assert.Greater(t, fi.Pix[o+2], float32(0))
+	o = fi.PixOffset(24+7, 24)
+	assert.Equal(t, []float32{0, 1, 0, 1}, fi.Pix[o:o+4])
+	// A disc at the center of the sphere is hidden by the line.
+	settings.Points[0].Shape = DiscPoint
+	fi = Run(settings).(*graphix.FloatImage)
+	o = fi.PixOffset(24, 24)
+	assert.Equal(t, []float32{0, 1, 0, 1}, fi.Pix[o:o+4])
+
+	// Tiled rendering is the same as the one of a single worker.
+	rng := rand.New(rand.NewPCG(3, 4))
+	settings.Points = nil
+	for i := range 200 {
+		settings.Points = append(settings.Points, &SpacePoint{
+			Pos:       graphix.NewVec3(10*rng.Float64()-5, 10*rng.Float64()-5, 10*rng.Float64()-5),
+			Color:     color.NRGBA{R: uint8(rng.IntN(256)), G: 0xff, A: uint8(rng.IntN(256))},
+			Shape:     PointShape(i % 5),
+			Size:      10 * rng.Float64(),
+			WorldSize: i%2 == 0,
+		})
+	}
+	settings.Workers = 1
+	fi = Run(settings).(*graphix.FloatImage)
+	for _, workers := range []int{1, 3} {
+		settings.TileSize, settings.Workers = 8, workers
+		assert.Equal(t, fi, Run(settings), "workers %v", workers)
+	}
+
+	// Supersampling scales the sizes in pixels.
+	settings.Paths = nil
+	settings.Points = []*SpacePoint{{Pos: graphix.NewVec3(0, 0, 0), Color: red, Shape: SquareMarker, Size: 20}}
+	settings.Supersampling = 3
+	assert.InDelta(t, 400, area(Run(settings).(*graphix.FloatImage)), 1)
+}
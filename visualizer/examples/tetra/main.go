@@ -44,17 +44,19 @@ func main() {
 	}
 
 	sr := 0.02
+	var sinks []graphix.Volume
+	for _, charge := range negatives {
+		sinks = append(sinks, graphix.NewSphere(charge, sr))
+	}
 	atEnd := func(x, tan *graphix.Vec3, f int) bool {
 		// Stop if the field is too weak.
 		if tan.Dot(tan) < 1e-12 {
 			// fmt.Printf("ended at x=%v,tan=%v, small tan\n", x, tan)
 			return true
 		}
-		tmp := graphix.BlankVec3()
 		// Stop if we are close the negative charges.
-		for _, charge := range negatives {
-			tmp.Sub(x, charge)
-			if tmp.Dot(tmp) < sr*sr {
+		for _, sink := range sinks {
+			if sink.Contains(x) {
 				// fmt.Printf("ended at x=%v,tan=%v, too close to negative\n", x, tan)
 				return true
 			}
@@ -44,6 +44,15 @@ type Trajectory struct {
 type TrajectoryVisualAttributes struct {
 	LineWidth float64
 	syms      []*symmetry
+	// Optional line width mapping from the tangent length, see SetTangentWidth.
+	tanWidth *tangentWidth
+}
+
+// Maps the tangent length into line width.
+type tangentWidth struct {
+	minWidth float64
+	maxWidth float64
+	gamma    float64
 }
 
 // NewTrajectoryVisualAttributes creates a new TrajectoryVisualAttributes with the specified line width and color.
@@ -69,6 +78,25 @@ func (tva *TrajectoryVisualAttributes) AddSymmetry(
 	return tva
 }
 
+// SetTangentWidth makes the line width vary along the trajectories with the tangent length: the line width is
+// minWidth and maxWidth at the min and max tangent values, intermediate tangent values will be linearly interpolated
+// and then gamma corrected by gamma, like the fading in VisualizeSettings. LineWidth is then no longer used.
+// It panics unless minWidth and maxWidth are positive and finite, since a width of 0 would fall back to LineWidth.
+func (tva *TrajectoryVisualAttributes) SetTangentWidth(minWidth, maxWidth, gamma float64) *TrajectoryVisualAttributes {
+	for _, w := range []float64{minWidth, maxWidth} {
+		if !(w > 0 && !math.IsInf(w, 1)) {
+			panic(fmt.Sprintf("tangent widths must be positive and finite, got %v and %v", minWidth, maxWidth))
+		}
+	}
+	tva.tanWidth = &tangentWidth{minWidth: minWidth, maxWidth: maxWidth, gamma: gamma}
+	return tva
+}
+
+// Maps tan within the global tangent range into [0,1], gamma corrected by gamma.
+func tangentFactor(tan, globalMinTan, globalMaxTan, gamma float64) float64 {
+	return math.Pow((tan-globalMinTan)/(globalMaxTan-globalMinTan), gamma)
+}
+
 func (vt *Trajectory) spacePaths(
 	settings *VisualizeSettings,
 	vta *TrajectoryVisualAttributes,
@@ -80,6 +108,32 @@ func (vt *Trajectory) spacePaths(
 		return paths
 	}
 
+	// Line width at point i, 0 means the LineWidth of the path.
+	width := func(i int) float64 {
+		if vta.tanWidth == nil {
+			return 0
+		}
+		tw := vta.tanWidth
+		return tw.minWidth + (tw.maxWidth-tw.minWidth)*tangentFactor(
+			vt.points[i].tan,
+			globalMinTan,
+			globalMaxTan,
+			tw.gamma,
+		)
+	}
+
+	// Returns c faded by the fading factor of tangent tan.
+	fadedColor := func(c color.NRGBA64, tan float64) color.NRGBA64 {
+		fading := settings.MinFading + (settings.MaxFading-settings.MinFading)*tangentFactor(
+			tan,
+			globalMinTan,
+			globalMaxTan,
+			settings.FadingGamma,
+		)
+		c.A = uint16(float64(c.A) * fading)
+		return c
+	}
+
 	for _, sym := range vta.syms {
 		path := &zraster.SpacePath{
 			End: sym.transform.Apply(
@@ -87,23 +141,21 @@ func (vt *Trajectory) spacePaths(
 				vt.points[len(vt.points)-1].pos,
 			),
 			LineWidth: vta.LineWidth,
+			EndWidth:  width(len(vt.points) - 1),
+		}
+		if settings.SmoothFading {
+			path.EndColor = fadedColor(sym.color, vt.points[len(vt.points)-1].tan)
 		}
 		for i := 0; i < len(vt.points)-1; i++ {
-			// Take the average tangent between the two endpoints, then calculate the fading factor.
-			tan := (vt.points[i].tan + vt.points[i+1].tan) / 2
-			fading := settings.MinFading + (settings.MaxFading-settings.MinFading)*math.Pow(
-				(tan-globalMinTan)/(globalMaxTan-globalMinTan),
-				settings.FadingGamma,
-			)
-
+			tan := vt.points[i].tan
+			if !settings.SmoothFading {
+				// Take the average tangent between the two endpoints.
+				tan = (tan + vt.points[i+1].tan) / 2
+			}
 			path.Segments = append(path.Segments, &zraster.SpaceVertex{
-				Pos: sym.transform.Apply(graphix.BlankVec3(), vt.points[i].pos),
-				Color: color.NRGBA64{
-					R: sym.color.R,
-					G: sym.color.G,
-					B: sym.color.B,
-					A: uint16(float64(sym.color.A) * fading),
-				},
+				Pos:   sym.transform.Apply(graphix.BlankVec3(), vt.points[i].pos),
+				Color: fadedColor(sym.color, tan),
+				Width: width(i),
 			})
 		}
 		paths = append(paths, path)
@@ -0,0 +1,31 @@
+package visualizer
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/euphoricrhino/go-common/graphix"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrajectoryTangentWidth(t *testing.T) {
+	vt := &Trajectory{points: []*renderPoint{
+		{tan: 1, pos: graphix.NewVec3(0, 0, 0)},
+		{tan: 2, pos: graphix.NewVec3(1, 0, 0)},
+		{tan: 3, pos: graphix.NewVec3(2, 0, 0)},
+	}}
+	settings := &VisualizeSettings{MinFading: 1, MaxFading: 1, FadingGamma: 1}
+	vta := NewTrajectoryVisualAttributes(5, color.NRGBA64{R: 0xffff, A: 0xffff}).SetTangentWidth(.5, 2, 1)
+	paths := vt.spacePaths(settings, vta, 1, 3)
+	assert.Len(t, paths, 1)
+	// The thinnest vertex has its own width instead of falling back to LineWidth.
+	assert.Equal(t, .5, paths[0].Segments[0].Width)
+	assert.Equal(t, 1.25, paths[0].Segments[1].Width)
+	assert.Equal(t, 2.0, paths[0].EndWidth)
+
+	// A width of 0 would fall back to LineWidth.
+	assert.PanicsWithValue(t, "tangent widths must be positive and finite, got 0 and 2", func() { vta.SetTangentWidth(0, 2, 1) })
+	assert.Panics(t, func() { vta.SetTangentWidth(.5, math.Inf(1), 1) })
+	assert.Panics(t, func() { vta.SetTangentWidth(math.NaN(), 2, 1) })
+}
@@ -21,6 +21,19 @@ type VisualizeSettings struct {
 	FadingGamma float64
 	// Concurrency.
 	Workers int
+	// Precision of the rendered images, e.g., zraster.RGBA16 to save 16-bit PNG files with SavePNG,
+	// or zraster.Float32 to save PFM files with SavePFM.
+	Format zraster.OutputFormat
+	// Optional point primitives drawn and depth sorted together with the streamlines, e.g., charges or seed points.
+	Points []*zraster.SpacePoint
+	// Optional attenuation of the streamlines by their z-distance from the camera.
+	DepthCue *zraster.DepthCue
+	// Whether to fade the streamlines continuously with the tangent at each point, instead of fading each line
+	// segment uniformly with the average tangent of its end points.
+	SmoothFading bool
+	// Optional stereo rendering. If set, each camera along CameraOrbit is used as the center camera of a stereo rig
+	// and the left and right images are composed into one image.
+	Stereo *zraster.StereoSettings
 	// Map from camera frame index to trajectory frame index.
 	FrameMapper func(f int) int
 	// User-provided callback functions for each generated image, together with the camera frame index.
@@ -29,19 +42,7 @@ type VisualizeSettings struct {
 
 // VisualizeStreamlines visualizes the traced streamlines.
 func VisualizeStreamlines(settings VisualizeSettings, vtfs []*VisualTrajectoryFrame) {
-	// Calculate max and min of tangent lengths.
-	maxTan, minTan := math.Inf(-1), math.Inf(1)
-	for _, vtf := range vtfs {
-		if vtf.stats == nil {
-			vtf.load(true, settings.Workers)
-		}
-		minTan = math.Min(minTan, vtf.stats.minTan)
-		maxTan = math.Max(maxTan, vtf.stats.maxTan)
-	}
-	// Degenerate case - all tan's are exactly the same.
-	if maxTan == minTan {
-		maxTan, minTan = 1, 0
-	}
+	minTan, maxTan := TangentRange(settings, vtfs)
 
 	for j, vtf := range vtfs {
 		var cameraFrames []int
@@ -56,11 +57,22 @@ func VisualizeStreamlines(settings VisualizeSettings, vtfs []*VisualTrajectoryFr
 		}
 		paths := vtf.spacePaths(&settings, minTan, maxTan)
 		for _, cameraFrame := range cameraFrames {
-			img := zraster.Run(zraster.Settings{
-				Camera:  settings.CameraOrbit.GetCamera(cameraFrame),
-				Paths:   paths,
-				Workers: settings.Workers,
-			})
+			zsettings := zraster.Settings{
+				Camera:   settings.CameraOrbit.GetCamera(cameraFrame),
+				Paths:    paths,
+				Points:   settings.Points,
+				Workers:  settings.Workers,
+				Format:   settings.Format,
+				DepthCue: settings.DepthCue,
+				// Only the fading changes along the streamlines.
+				SmoothColors: settings.SmoothFading,
+			}
+			var img draw.Image
+			if settings.Stereo != nil {
+				img = zraster.RunStereo(zsettings, *settings.Stereo)
+			} else {
+				img = zraster.Run(zsettings)
+			}
 			for _, cb := range settings.ImageCallbacks {
 				cb(img, cameraFrame)
 			}
@@ -68,6 +80,43 @@ func VisualizeStreamlines(settings VisualizeSettings, vtfs []*VisualTrajectoryFr
 	}
 }
 
+// TangentRange returns the range of tangent lengths over all vtfs, which VisualizeStreamlines maps to the fading
+// range, e.g., to label a colorbar made with graphix.NewFadingColormap.
+func TangentRange(settings VisualizeSettings, vtfs []*VisualTrajectoryFrame) (float64, float64) {
+	// Calculate max and min of tangent lengths.
+	maxTan, minTan := math.Inf(-1), math.Inf(1)
+	for _, vtf := range vtfs {
+		if vtf.stats == nil {
+			vtf.load(true, settings.Workers)
+		}
+		minTan = math.Min(minTan, vtf.stats.minTan)
+		maxTan = math.Max(maxTan, vtf.stats.maxTan)
+	}
+	// Degenerate case - all tan's are exactly the same.
+	if maxTan == minTan {
+		maxTan, minTan = 1, 0
+	}
+	return minTan, maxTan
+}
+
+// An image callback that draws the colorbar defined by settings on the image.
+func DrawColorbar(settings graphix.ColorbarSettings) func(draw.Image, int) {
+	return func(img draw.Image, _ int) {
+		if err := graphix.DrawColorbar(img, settings); err != nil {
+			panic(fmt.Sprintf("failed to draw colorbar: %v", err))
+		}
+	}
+}
+
+// An image callback that draws the legend defined by settings on the image.
+func DrawLegend(settings graphix.LegendSettings) func(draw.Image, int) {
+	return func(img draw.Image, _ int) {
+		if err := graphix.DrawLegend(img, settings); err != nil {
+			panic(fmt.Sprintf("failed to draw legend: %v", err))
+		}
+	}
+}
+
 // An image callback that saves the image as png file in the given directory.
 func SavePNG(outDir string) func(draw.Image, int) {
 	return func(img draw.Image, f int) {
@@ -83,3 +132,34 @@ func SavePNG(outDir string) func(draw.Image, int) {
 		fmt.Printf("generated %v\n", fn)
 	}
 }
+
+// An image callback that saves the image as pfm file in the given directory, see graphix.EncodePFM.
+func SavePFM(outDir string) func(draw.Image, int) {
+	return func(img draw.Image, f int) {
+		fn := filepath.Join(outDir, fmt.Sprintf("frame-%04v.pfm", f))
+		file, err := os.Create(fn)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create output file '%v': %v", fn, err))
+		}
+		if err := graphix.EncodePFM(file, img); err != nil {
+			panic(fmt.Sprintf("failed to encode to PFM: %v", err))
+		}
+		_ = file.Close()
+		fmt.Printf("generated %v\n", fn)
+	}
+}
+
+// An image callback that saves the camera of the frame along orbit as a JSON file in the given directory,
+// next to the images saved by SavePNG. For stereo rendering, the center camera is saved.
+func SaveCameraJSON(outDir string, orbit graphix.CameraOrbit) func(draw.Image, int) {
+	return func(_ draw.Image, f int) {
+		data, err := graphix.MarshalCamera(orbit.GetCamera(f))
+		if err != nil {
+			panic(fmt.Sprintf("failed to encode camera to JSON: %v", err))
+		}
+		fn := filepath.Join(outDir, fmt.Sprintf("frame-%04v.json", f))
+		if err := os.WriteFile(fn, data, 0o644); err != nil {
+			panic(fmt.Sprintf("failed to write output file '%v': %v", fn, err))
+		}
+	}
+}